@@ -0,0 +1,173 @@
+package jsonast
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+func NewNull() *JsonValue {
+	return &JsonValue{Null: &JsonNull{}}
+}
+
+// NewAny returns the shape that accepts any value.
+func NewAny() *JsonValue {
+	return &JsonValue{Null: &JsonNull{any: true}}
+}
+
+func NewBool(b bool) *JsonValue {
+	if b {
+		return &JsonValue{True: &JsonTrue{}}
+	}
+
+	return &JsonValue{False: &JsonFalse{}}
+}
+
+func NewNullableBool(b bool) *JsonValue {
+	v := NewBool(b)
+	v.SetNullable(true) //nolint:errcheck
+	return v
+}
+
+func NewString(s string) *JsonValue {
+	return &JsonValue{String: &JsonString{Text: s}}
+}
+
+func NewNullableString(s string) *JsonValue {
+	v := NewString(s)
+	v.String.SetNullable(true)
+	return v
+}
+
+func NewNumberFromInt(n int64) *JsonValue {
+	return &JsonValue{Number: &JsonNumber{Text: strconv.FormatInt(n, 10)}}
+}
+
+// NewNumberFromFloat returns the shortest decimal text that round-trips f.
+// NaN and infinities have no JSON representation.
+func NewNumberFromFloat(f float64) (*JsonValue, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%v is not a valid JSON number", f)
+	}
+
+	return &JsonValue{Number: &JsonNumber{Text: strconv.FormatFloat(f, 'g', -1, 64)}}, nil
+}
+
+// NewNumberFromText validates text against the JSON number grammar.
+func NewNumberFromText(text string) (*JsonValue, error) {
+	if !isJSONNumber(text) {
+		return nil, fmt.Errorf("%q is not a valid JSON number", text)
+	}
+
+	return &JsonValue{Number: &JsonNumber{Text: text}}, nil
+}
+
+func NewNullableNumber(text string) (*JsonValue, error) {
+	v, err := NewNumberFromText(text)
+
+	if err != nil {
+		return nil, err
+	}
+
+	v.Number.SetNullable(true)
+	return v, nil
+}
+
+func NewMember(key string, v *JsonValue) *JsonObjectMember {
+	return &JsonObjectMember{Key: key, Value: v}
+}
+
+func NewObject(members ...*JsonObjectMember) *JsonValue {
+	return &JsonValue{Object: &JsonObject{Members: members}}
+}
+
+func NewArray(elems ...*JsonValue) *JsonValue {
+	return &JsonValue{Array: &JsonArray{Elements: elems}}
+}
+
+// ObjectBuilder builds an object member by member.
+// The first error is kept and returned by Build; later calls are ignored.
+type ObjectBuilder struct {
+	members []*JsonObjectMember
+	err     error
+}
+
+func NewObjectBuilder() *ObjectBuilder {
+	return &ObjectBuilder{}
+}
+
+func (b *ObjectBuilder) Set(key string, v *JsonValue) *ObjectBuilder {
+	if b.err == nil {
+		b.members = append(b.members, NewMember(key, v))
+	}
+
+	return b
+}
+
+func (b *ObjectBuilder) setOrFail(key string, v *JsonValue, err error) *ObjectBuilder {
+	if err != nil {
+		if b.err == nil {
+			b.err = fmt.Errorf("%s: %w", appendPointer("", key), err)
+		}
+
+		return b
+	}
+
+	return b.Set(key, v)
+}
+
+func (b *ObjectBuilder) Null(key string) *ObjectBuilder {
+	return b.Set(key, NewNull())
+}
+
+func (b *ObjectBuilder) Bool(key string, v bool) *ObjectBuilder {
+	return b.Set(key, NewBool(v))
+}
+
+func (b *ObjectBuilder) String(key string, s string) *ObjectBuilder {
+	return b.Set(key, NewString(s))
+}
+
+func (b *ObjectBuilder) Int(key string, n int64) *ObjectBuilder {
+	return b.Set(key, NewNumberFromInt(n))
+}
+
+func (b *ObjectBuilder) Float(key string, f float64) *ObjectBuilder {
+	v, err := NewNumberFromFloat(f)
+	return b.setOrFail(key, v, err)
+}
+
+func (b *ObjectBuilder) Number(key string, text string) *ObjectBuilder {
+	v, err := NewNumberFromText(text)
+	return b.setOrFail(key, v, err)
+}
+
+func (b *ObjectBuilder) Array(key string, elems ...*JsonValue) *ObjectBuilder {
+	return b.Set(key, NewArray(elems...))
+}
+
+// Object adds a nested object filled in by build.
+func (b *ObjectBuilder) Object(key string, build func(*ObjectBuilder)) *ObjectBuilder {
+	nested := NewObjectBuilder()
+	build(nested)
+	v, err := nested.Build()
+
+	if err != nil {
+		// Nested errors already carry their own path; prefix ours.
+		if b.err == nil {
+			b.err = fmt.Errorf("%s%w", appendPointer("", key), err)
+		}
+
+		return b
+	}
+
+	return b.Set(key, v)
+}
+
+func (b *ObjectBuilder) Build() (*JsonValue, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return NewObject(b.members...), nil
+}
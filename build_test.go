@@ -0,0 +1,112 @@
+package jsonast_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestNewNumber(t *testing.T) {
+	assert.Equal(t, "-42", jsonast.NewNumberFromInt(-42).Number.Text)
+
+	tests := []struct {
+		f        float64
+		expected string
+	}{
+		{0, "0"},
+		{1.5, "1.5"},
+		{-0.001, "-0.001"},
+		{1e21, "1e+21"},
+		{123456789, "1.23456789e+08"},
+	}
+
+	for _, tt := range tests {
+		v, err := jsonast.NewNumberFromFloat(tt.f)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, v.Number.Text)
+
+		// the text is always a valid JSON number
+		_, err = jsonast.NewNumberFromText(v.Number.Text)
+		assert.NoError(t, err)
+	}
+
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err := jsonast.NewNumberFromFloat(f)
+		assert.Error(t, err)
+	}
+}
+
+func TestNewNumberFromText(t *testing.T) {
+	for _, text := range []string{"0", "-0", "1.5", "1e10", "-1.5E-3"} {
+		v, err := jsonast.NewNumberFromText(text)
+		require.NoError(t, err)
+		assert.Equal(t, text, v.Number.Text)
+	}
+
+	for _, text := range []string{"", "01", "+1", ".5", "1.", "0x10", "NaN", "1e", " 1", "1 ", "1\n", "-1\t", "1 2", "-", "1e+", "00", "-01"} {
+		_, err := jsonast.NewNumberFromText(text)
+		assert.EqualError(t, err, strconv.Quote(text)+" is not a valid JSON number")
+	}
+}
+
+func TestNewNullable(t *testing.T) {
+	assert.True(t, jsonast.NewNullableString("s").String.Nullable())
+	assert.False(t, jsonast.NewString("s").String.Nullable())
+	assert.True(t, jsonast.NewNullableBool(true).True.Nullable())
+	assert.True(t, jsonast.NewNullableBool(false).False.Nullable())
+
+	n, err := jsonast.NewNullableNumber("1")
+	require.NoError(t, err)
+	assert.True(t, n.Number.Nullable())
+
+	_, err = jsonast.NewNullableNumber("x")
+	assert.Error(t, err)
+}
+
+func TestNewObject(t *testing.T) {
+	v := jsonast.NewObject(
+		jsonast.NewMember("a", jsonast.NewArray(jsonast.NewNumberFromInt(1), jsonast.NewNull())),
+		jsonast.NewMember("b", jsonast.NewBool(false)),
+	)
+
+	expected, err := jsonast.ParseBytes("", []byte(`{"a":[1,null],"b":false}`))
+	require.NoError(t, err)
+	assert.Equal(t, stripSource(expected), v)
+}
+
+func TestObjectBuilder(t *testing.T) {
+	v, err := jsonast.NewObjectBuilder().
+		String("name", "x").
+		Int("n", 1).
+		Float("f", 0.5).
+		Number("big", "1e400").
+		Bool("ok", true).
+		Null("nil").
+		Array("list", jsonast.NewString("a")).
+		Object("nested", func(b *jsonast.ObjectBuilder) {
+			b.Set("empty", jsonast.NewObject())
+		}).
+		Build()
+
+	require.NoError(t, err)
+
+	b, err := v.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"x","n":1,"f":0.5,"big":1e400,"ok":true,"nil":null,"list":["a"],"nested":{"empty":{}}}`, string(b))
+}
+
+func TestObjectBuilder_Error(t *testing.T) {
+	_, err := jsonast.NewObjectBuilder().
+		String("a", "x").
+		Object("b", func(b *jsonast.ObjectBuilder) {
+			b.Number("c/d", "1.").Number("e", "x")
+		}).
+		Float("f", math.NaN()).
+		Build()
+
+	assert.EqualError(t, err, `/b/c~1d: "1." is not a valid JSON number`)
+}
@@ -0,0 +1,154 @@
+package jsonast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Canonical serializes v per RFC 8785 (JSON Canonicalization Scheme).
+func (v *JsonValue) Canonical() ([]byte, error) {
+	return v.appendCanonical(nil, "")
+}
+
+func (v *JsonValue) appendCanonical(buf []byte, path string) ([]byte, error) {
+	switch o := v.Value().(type) {
+	case *JsonFalse:
+		return append(buf, "false"...), nil
+	case *JsonNull:
+		return append(buf, "null"...), nil
+	case *JsonTrue:
+		return append(buf, "true"...), nil
+	case *JsonNumber:
+		n, err := canonicalNumber(o.Text)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		return append(buf, n...), nil
+	case *JsonString:
+		if _, msg, ok := validateIJSONString(o.Raw, o.Text); !ok {
+			return nil, fmt.Errorf("%s: %s", path, msg)
+		}
+
+		return appendString(buf, o.Text), nil
+	case *JsonObject:
+		members := slices.Clone(o.Members)
+
+		slices.SortStableFunc(members, func(a, b *JsonObjectMember) int {
+			return slices.Compare(utf16.Encode([]rune(a.Key)), utf16.Encode([]rune(b.Key)))
+		})
+
+		buf = append(buf, '{')
+
+		for i, m := range members {
+			mpath := appendPointer(path, m.Key)
+
+			if i > 0 {
+				if members[i-1].Key == m.Key {
+					return nil, fmt.Errorf("%s: duplicate key %q", mpath, m.Key)
+				}
+
+				buf = append(buf, ',')
+			}
+
+			if _, msg, ok := validateIJSONString(m.RawKey, m.Key); !ok {
+				return nil, fmt.Errorf("%s: key: %s", mpath, msg)
+			}
+
+			buf = appendString(buf, m.Key)
+			buf = append(buf, ':')
+			var err error
+
+			if buf, err = m.Value.appendCanonical(buf, mpath); err != nil {
+				return nil, err
+			}
+		}
+
+		return append(buf, '}'), nil
+	case *JsonArray:
+		buf = append(buf, '[')
+
+		for i, e := range o.Elements {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+
+			var err error
+
+			if buf, err = e.appendCanonical(buf, appendIndexPointer(path, i)); err != nil {
+				return nil, err
+			}
+		}
+
+		return append(buf, ']'), nil
+	default:
+		return nil, fmt.Errorf("%s: cannot canonicalize an invalid or empty value", path)
+	}
+}
+
+// canonicalNumber formats text the way ECMAScript's Number.prototype.toString does.
+func canonicalNumber(text string) (string, error) {
+	f, err := strconv.ParseFloat(text, 64)
+
+	if errors.Is(err, strconv.ErrRange) && math.IsInf(f, 0) {
+		return "", fmt.Errorf("number %s is out of IEEE 754 double range", text)
+	} else if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return "", err
+	}
+
+	if f == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// d.ddddde±x: digits are the shortest round-tripping decimal, n is the decimal point position.
+	e := strconv.FormatFloat(f, 'e', -1, 64)
+	mant, exp, _ := strings.Cut(e, "e")
+	digits := strings.Replace(mant, ".", "", 1)
+	x, _ := strconv.Atoi(exp)
+	k := len(digits)
+	n := x + 1
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k), nil
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:], nil
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits, nil
+	}
+
+	expSign := "+"
+
+	if n-1 < 0 {
+		expSign = "-"
+	}
+
+	frac := ""
+
+	if k > 1 {
+		frac = "." + digits[1:]
+	}
+
+	return sign + digits[:1] + frac + "e" + expSign + strconv.Itoa(abs(n-1)), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
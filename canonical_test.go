@@ -0,0 +1,117 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected string
+	}{
+		{
+			name:     "rfc8785 example",
+			json:     `{"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001], "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/", "literals": [null, true, false]}`,
+			expected: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			name:     "utf-16 key order",
+			json:     `{"\u20ac": "Euro Sign", "\r": "Carriage Return", "\ufb33": "Hebrew Letter Dalet With Dagesh", "1": "One", "\ud83d\ude00": "Emoji: Grinning Face", "\u0080": "Control", "\u00f6": "Latin Small Letter O With Diaeresis"}`,
+			expected: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{
+			name:     "nested",
+			json:     "{ \"b\" : [ {\"z\":1, \"y\":{}} , [ ] ],\n \"a\": \"\" }",
+			expected: `{"a":"","b":[{"y":{},"z":1},[]]}`,
+		},
+		{
+			name:     "scalar",
+			json:     ` "x" `,
+			expected: `"x"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := jsonast.ParseBytes("<filename>", []byte(tt.json))
+			require.NoError(t, err)
+			b, err := v.Canonical()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+		})
+	}
+}
+
+func TestCanonical_Numbers(t *testing.T) {
+	tests := map[string]string{
+		"0":                       "0",
+		"-0":                      "0",
+		"0.0e10":                  "0",
+		"1":                       "1",
+		"-1.0":                    "-1",
+		"1e20":                    "100000000000000000000",
+		"1e21":                    "1e+21",
+		"123456789012345678901":   "123456789012345680000",
+		"1234567890123456789012":  "1.2345678901234568e+21",
+		"0.000001":                "0.000001",
+		"0.0000001":               "1e-7",
+		"-1.5e-7":                 "-1.5e-7",
+		"9007199254740993":        "9007199254740992",
+		"5e-324":                  "5e-324",
+		"1.7976931348623157e308":  "1.7976931348623157e+308",
+		"-1.7976931348623157e308": "-1.7976931348623157e+308",
+		"123.456":                 "123.456",
+		"1e-400":                  "0",
+	}
+
+	for text, expected := range tests {
+		t.Run(text, func(t *testing.T) {
+			b, err := (&jsonast.JsonValue{Number: vnum(text)}).Canonical()
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(b))
+		})
+	}
+}
+
+func TestCanonical_Err(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		err  string
+	}{
+		{"overflow", `{"a":[1e400]}`, "/a/0: number 1e400 is out of IEEE 754 double range"},
+		{"duplicate key", `{"a":{"k":1,"k":2}}`, `/a/k: duplicate key "k"`},
+		{"lone surrogate", `["\udead"]`, `/0: lone surrogate \udead`},
+		{"lone surrogate key", `{"\udead":1}`, `/` + "\ufffd" + `: key: lone surrogate \udead`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := jsonast.ParseBytes("<filename>", []byte(tt.json))
+			require.NoError(t, err)
+			_, err = v.Canonical()
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+
+	_, err := (&jsonast.JsonValue{}).Canonical()
+	assert.EqualError(t, err, ": cannot canonicalize an invalid or empty value")
+}
+
+func TestCanonical_Deterministic(t *testing.T) {
+	a, err := jsonast.ParseBytes("<filename>", []byte(`{"b":2.0,"a":[1,"x"]}`))
+	require.NoError(t, err)
+	b, err := jsonast.ParseBytes("<filename>", []byte("{\n  \"a\": [1e0, \"\\u0078\"],\n  \"b\": 2\n}"))
+	require.NoError(t, err)
+
+	ca, err := a.Canonical()
+	require.NoError(t, err)
+	cb, err := b.Canonical()
+	require.NoError(t, err)
+	assert.Equal(t, ca, cb)
+}
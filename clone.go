@@ -0,0 +1,84 @@
+package jsonast
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of v that shares no nodes, slices or maps with v.
+func (v *JsonValue) Clone() *JsonValue {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+
+	if v.Syntax != nil {
+		syn := *v.Syntax
+		syn.Commas = slices.Clone(v.Syntax.Commas)
+		c.Syntax = &syn
+	}
+
+	switch {
+	case v.False != nil:
+		x := *v.False
+		c.False = &x
+	case v.Null != nil:
+		x := *v.Null
+		c.Null = &x
+	case v.True != nil:
+		x := *v.True
+		c.True = &x
+	case v.Number != nil:
+		x := *v.Number
+		c.Number = &x
+	case v.String != nil:
+		x := *v.String
+		c.String = &x
+	case v.Invalid != nil:
+		x := *v.Invalid
+		c.Invalid = &x
+	case v.Array != nil:
+		c.Array = v.Array.clone()
+	case v.Object != nil:
+		c.Object = v.Object.clone()
+	}
+
+	return &c
+}
+
+func (v *JsonArray) clone() *JsonArray {
+	x := *v
+	x.Elements = slices.Clone(v.Elements)
+
+	for i, e := range x.Elements {
+		x.Elements[i] = e.Clone()
+	}
+
+	return &x
+}
+
+func (v *JsonObject) clone() *JsonObject {
+	x := *v
+	x.Members = slices.Clone(v.Members)
+
+	for i, m := range x.Members {
+		x.Members[i] = m.clone()
+	}
+
+	x.OmittableKeys = maps.Clone(v.OmittableKeys)
+	x.index = nil
+	return &x
+}
+
+func (m *JsonObjectMember) clone() *JsonObjectMember {
+	c := *m
+
+	if m.Syntax != nil {
+		syn := *m.Syntax
+		c.Syntax = &syn
+	}
+
+	c.Value = m.Value.Clone()
+	return &c
+}
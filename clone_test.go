@@ -0,0 +1,35 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestClone(t *testing.T) {
+	v, err := jsonast.ParseLossless("a.json", []byte(`{ "a": [1, "s", {"b": null}], "c": true }`))
+	require.NoError(t, err)
+	v.Object.OmittableKeys = map[string]struct{}{"c": {}}
+
+	orig, err := v.MarshalJSON()
+	require.NoError(t, err)
+
+	c := v.Clone()
+	assert.Equal(t, v, c)
+	assert.True(t, jsonast.Equal(v, c, &jsonast.EqualOptions{OrderedMembers: true}))
+
+	mutate(c)
+	c.Syntax.Commas[0] = "   "
+
+	b, err := v.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, string(orig), string(b))
+	assert.Equal(t, map[string]struct{}{"c": {}}, v.Object.OmittableKeys)
+}
+
+func TestClone_Nil(t *testing.T) {
+	var v *jsonast.JsonValue
+	assert.Nil(t, v.Clone())
+}
@@ -0,0 +1,190 @@
+package jsonast
+
+import "fmt"
+
+type IncompatibilityKind int
+
+const (
+	IncompatibilityKeyRemoved IncompatibilityKind = iota
+	IncompatibilityKeyOmittable
+	IncompatibilityNullable
+	IncompatibilityTypeChanged
+	IncompatibilityWidenedToAny
+	IncompatibilityKeyAdded
+	IncompatibilityKeyRequired
+	IncompatibilityNonNullable
+	IncompatibilityNarrowedFromAny
+)
+
+func (k IncompatibilityKind) String() string {
+	switch k {
+	case IncompatibilityKeyRemoved:
+		return "key removed"
+	case IncompatibilityKeyOmittable:
+		return "key became omittable"
+	case IncompatibilityNullable:
+		return "became nullable"
+	case IncompatibilityTypeChanged:
+		return "type changed"
+	case IncompatibilityWidenedToAny:
+		return "widened to any"
+	case IncompatibilityKeyAdded:
+		return "key added"
+	case IncompatibilityKeyRequired:
+		return "key became required"
+	case IncompatibilityNonNullable:
+		return "became non-nullable"
+	case IncompatibilityNarrowedFromAny:
+		return "narrowed from any"
+	default:
+		return fmt.Sprintf("IncompatibilityKind(%d)", int(k))
+	}
+}
+
+// Incompatibility is a place where a value of one shape may not fit another.
+// Path is a JSON Pointer; "*" stands for every element of an array.
+type Incompatibility struct {
+	Kind IncompatibilityKind
+	Path string
+	Msg  string
+}
+
+func (i *Incompatibility) Error() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Msg)
+}
+
+// CheckBackward reports where data of the new shape may break readers of the old shape.
+func CheckBackward(oldShape, newShape *JsonValue) []*Incompatibility {
+	return checkFits(oldShape, newShape, false)
+}
+
+// CheckForward reports where data of the old shape may break readers of the new shape.
+func CheckForward(oldShape, newShape *JsonValue) []*Incompatibility {
+	return checkFits(newShape, oldShape, true)
+}
+
+// checkFits checks source against target; forward tells whether source is the old shape,
+// so that findings are worded from the old shape to the new one.
+func checkFits(target, source *JsonValue, forward bool) []*Incompatibility {
+	c := &compatChecker{incompats: []*Incompatibility{}, forward: forward}
+	c.fits(target, source, "")
+	return c.incompats
+}
+
+type compatChecker struct {
+	incompats []*Incompatibility
+	forward   bool
+}
+
+func (c *compatChecker) report(kind IncompatibilityKind, path string, format string, args ...any) {
+	c.incompats = append(c.incompats, &Incompatibility{Kind: kind, Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+func shapeKind(v *JsonValue) string {
+	switch {
+	case v.IsTrue() || v.IsFalse():
+		return "boolean"
+	case v.IsNull():
+		if v.IsAny() {
+			return "any"
+		}
+
+		return "null"
+	case v.IsNumber():
+		return "number"
+	case v.IsString():
+		return "string"
+	case v.IsObject():
+		return "object"
+	case v.IsArray():
+		return "array"
+	default:
+		return "invalid"
+	}
+}
+
+// fits checks that every value described by source is accepted by target.
+func (c *compatChecker) fits(target, source *JsonValue, path string) {
+	tk, sk := shapeKind(target), shapeKind(source)
+
+	switch {
+	case tk == "any":
+		return
+	case sk == "any":
+		if c.forward {
+			c.report(IncompatibilityNarrowedFromAny, path, "any narrowed to %s", tk)
+		} else {
+			c.report(IncompatibilityWidenedToAny, path, "%s widened to any", tk)
+		}
+
+		return
+	case sk == "null" && tk != "null":
+		if target.Nullable() {
+			return
+		}
+
+		if c.forward {
+			c.report(IncompatibilityNonNullable, path, "null became %s", tk)
+		} else {
+			c.report(IncompatibilityNullable, path, "%s became null", tk)
+		}
+
+		return
+	case tk != sk:
+		if c.forward {
+			c.report(IncompatibilityTypeChanged, path, "type changed from %s to %s", sk, tk)
+		} else {
+			c.report(IncompatibilityTypeChanged, path, "type changed from %s to %s", tk, sk)
+		}
+
+		return
+	}
+
+	if source.Nullable() && !target.Nullable() {
+		if c.forward {
+			c.report(IncompatibilityNonNullable, path, "%s became non-nullable", tk)
+		} else {
+			c.report(IncompatibilityNullable, path, "%s became nullable", tk)
+		}
+	}
+
+	switch {
+	case target.IsObject():
+		c.fitsObject(target.Object, source.Object, path)
+	case target.IsArray():
+		if target.Array.Len() > 0 && source.Array.Len() > 0 {
+			c.fits(target.Array.UnionType(nil).Array.Elements[0], source.Array.UnionType(nil).Array.Elements[0], path+"/*")
+		}
+	}
+}
+
+func (c *compatChecker) fitsObject(target, source *JsonObject, path string) {
+	for _, k := range target.Keys() {
+		kpath := appendPointer(path, k)
+		tv, _ := target.Get(k)
+		sv, ok := source.Get(k)
+
+		switch {
+		case !ok:
+			if target.isOmittable(k) {
+				continue
+			}
+
+			if c.forward {
+				c.report(IncompatibilityKeyAdded, kpath, "required key %q is missing from old data", k)
+			} else {
+				c.report(IncompatibilityKeyRemoved, kpath, "required key %q was removed", k)
+			}
+
+			continue
+		case source.isOmittable(k) && !target.isOmittable(k):
+			if c.forward {
+				c.report(IncompatibilityKeyRequired, kpath, "omittable key %q became required", k)
+			} else {
+				c.report(IncompatibilityKeyOmittable, kpath, "required key %q became omittable", k)
+			}
+		}
+
+		c.fits(tv, sv, kpath)
+	}
+}
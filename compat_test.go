@@ -0,0 +1,134 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func inferShape(t *testing.T, docs ...string) *jsonast.JsonValue {
+	t.Helper()
+	inf := jsonast.NewInferrer(0)
+
+	for _, doc := range docs {
+		require.NoError(t, inf.AddBytes("", []byte(doc)))
+	}
+
+	return inf.Shape()
+}
+
+func incompatErrors(incompats []*jsonast.Incompatibility) []string {
+	errs := []string{}
+
+	for _, i := range incompats {
+		errs = append(errs, i.Kind.String()+": "+i.Error())
+	}
+
+	return errs
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      []string
+		new      []string
+		backward []string
+		forward  []string
+	}{
+		{
+			name:     "same",
+			old:      []string{`{"id":1,"tags":["a"]}`},
+			new:      []string{`{"id":2,"tags":[]}`},
+			backward: []string{},
+			forward:  []string{},
+		},
+		{
+			name:     "key removed and added",
+			old:      []string{`{"id":1,"name":"a"}`},
+			new:      []string{`{"id":1,"email":"e"}`},
+			backward: []string{`key removed: /name: required key "name" was removed`},
+			forward:  []string{`key added: /email: required key "email" is missing from old data`},
+		},
+		{
+			name:     "key became omittable",
+			old:      []string{`{"id":1,"a/b":"x"}`},
+			new:      []string{`{"id":1,"a/b":"x"}`, `{"id":2}`},
+			backward: []string{`key became omittable: /a~1b: required key "a/b" became omittable`},
+			forward:  []string{},
+		},
+		{
+			name:     "became nullable",
+			old:      []string{`{"n":1,"s":"x"}`},
+			new:      []string{`{"n":1,"s":"x"}`, `{"n":null,"s":null}`},
+			backward: []string{`became nullable: /n: number became nullable`, `became nullable: /s: string became nullable`},
+			forward:  []string{},
+		},
+		{
+			name:     "became null",
+			old:      []string{`{"n":1}`},
+			new:      []string{`{"n":null}`},
+			backward: []string{`became nullable: /n: number became null`},
+			forward:  []string{`type changed: /n: type changed from number to null`},
+		},
+		{
+			name:     "type changed",
+			old:      []string{`{"n":1,"o":{"x":true}}`},
+			new:      []string{`{"n":"1","o":{"x":[]}}`},
+			backward: []string{`type changed: /n: type changed from number to string`, `type changed: /o/x: type changed from boolean to array`},
+			forward:  []string{`type changed: /n: type changed from number to string`, `type changed: /o/x: type changed from boolean to array`},
+		},
+		{
+			name:     "null became a type",
+			old:      []string{`{"n":null}`},
+			new:      []string{`{"n":1}`},
+			backward: []string{`type changed: /n: type changed from null to number`},
+			forward:  []string{`became non-nullable: /n: null became number`},
+		},
+		{
+			name:     "key became required",
+			old:      []string{`{"id":1,"v":true}`, `{"id":2}`},
+			new:      []string{`{"id":1,"v":true}`},
+			backward: []string{},
+			forward:  []string{`key became required: /v: omittable key "v" became required`},
+		},
+		{
+			name:     "became non-nullable",
+			old:      []string{`{"n":1}`, `{"n":null}`},
+			new:      []string{`{"n":1}`},
+			backward: []string{},
+			forward:  []string{`became non-nullable: /n: number became non-nullable`},
+		},
+		{
+			name:     "array element narrowed from any",
+			old:      []string{`{"a":[1,"x"]}`},
+			new:      []string{`{"a":[1,2]}`},
+			backward: []string{},
+			forward:  []string{`narrowed from any: /a/*: any narrowed to number`},
+		},
+		{
+			name:     "array element widened to any",
+			old:      []string{`{"a":[1,2]}`},
+			new:      []string{`{"a":[1,"x"]}`},
+			backward: []string{`widened to any: /a/*: number widened to any`},
+			forward:  []string{},
+		},
+		{
+			name:     "nested array objects",
+			old:      []string{`[{"id":1,"v":true}]`},
+			new:      []string{`[{"id":1},{"id":null,"v":false}]`},
+			backward: []string{`became nullable: /*/id: number became nullable`, `key became omittable: /*/v: required key "v" became omittable`},
+			forward:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldShape := inferShape(t, tt.old...)
+			newShape := inferShape(t, tt.new...)
+			assert.Equal(t, tt.backward, incompatErrors(jsonast.CheckBackward(oldShape, newShape)))
+			assert.Equal(t, tt.forward, incompatErrors(jsonast.CheckForward(oldShape, newShape)))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package jsonast
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type ShapeViolationKind int
+
+const (
+	ShapeViolationTypeMismatch ShapeViolationKind = iota
+	ShapeViolationUnexpectedNull
+	ShapeViolationMissingKey
+	ShapeViolationUnknownKey
+)
+
+func (k ShapeViolationKind) String() string {
+	switch k {
+	case ShapeViolationTypeMismatch:
+		return "type mismatch"
+	case ShapeViolationUnexpectedNull:
+		return "unexpected null"
+	case ShapeViolationMissingKey:
+		return "missing key"
+	case ShapeViolationUnknownKey:
+		return "unknown key"
+	default:
+		return fmt.Sprintf("ShapeViolationKind(%d)", int(k))
+	}
+}
+
+type ShapeViolation struct {
+	Kind ShapeViolationKind
+	Path string
+	Pos  lexer.Position
+	Msg  string
+}
+
+func (v *ShapeViolation) Error() string {
+	return fmt.Sprintf("%s: %s: %s", v.Pos, v.Path, v.Msg)
+}
+
+type ConformOptions struct {
+	// DisallowUnknownKeys reports keys that the shape does not know about.
+	// Inferred shapes are open by default, so extra keys are accepted.
+	DisallowUnknownKeys bool
+}
+
+// Conforms checks doc against an inferred shape and returns every violation found.
+// Omittable keys may be missing, nullable scalars may be null, any-nulls accept anything,
+// and every array element must fit the shape's element type.
+// Conforms does not modify shape, so one shape can be shared by concurrent calls.
+func Conforms(doc, shape *JsonValue, opts *ConformOptions) []*ShapeViolation {
+	if opts == nil {
+		opts = &ConformOptions{}
+	}
+
+	violations := []*ShapeViolation{}
+	conforms(doc, shape, "", opts, &violations)
+	return violations
+}
+
+func conforms(doc, shape *JsonValue, path string, opts *ConformOptions, violations *[]*ShapeViolation) {
+	report := func(kind ShapeViolationKind, path string, pos lexer.Position, format string, args ...any) {
+		*violations = append(*violations, &ShapeViolation{Kind: kind, Path: path, Pos: pos, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	dk, sk := shapeKind(doc), shapeKind(shape)
+
+	switch {
+	case sk == "any":
+		return
+	case dk == "null" && sk != "null":
+		if !shape.Nullable() {
+			report(ShapeViolationUnexpectedNull, path, doc.Pos, "expected %s, got null", sk)
+		}
+
+		return
+	case dk != sk:
+		report(ShapeViolationTypeMismatch, path, doc.Pos, "expected %s, got %s", sk, dk)
+		return
+	}
+
+	switch {
+	case shape.IsObject():
+		for _, k := range shape.Object.Keys() {
+			sv, _ := shape.Object.Get(k)
+
+			if dv, ok := doc.Object.Get(k); ok {
+				conforms(dv, sv, appendPointer(path, k), opts, violations)
+			} else if !shape.Object.isOmittable(k) {
+				report(ShapeViolationMissingKey, appendPointer(path, k), doc.Pos, "missing required key %q", k)
+			}
+		}
+
+		if !opts.DisallowUnknownKeys {
+			return
+		}
+
+		for i, m := range doc.Object.Members {
+			if j, _ := doc.Object.lastMember(m.Key); j == i && !shape.Object.Has(m.Key) {
+				report(ShapeViolationUnknownKey, appendPointer(path, m.Key), m.Pos, "unknown key %q", m.Key)
+			}
+		}
+	case shape.IsArray():
+		if shape.Array.Len() == 0 {
+			return
+		}
+
+		elem := shape.Array.UnionType(nil).Array.Elements[0]
+
+		for i, e := range doc.Array.Elements {
+			conforms(e, elem, appendIndexPointer(path, i), opts, violations)
+		}
+	}
+}
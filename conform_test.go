@@ -0,0 +1,108 @@
+package jsonast_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestConforms(t *testing.T) {
+	shape := inferShape(t,
+		`{"id":1,"name":"a","tags":["x"],"meta":1,"opt":true,"items":[{"n":1}]}`,
+		`{"id":2,"name":null,"tags":[],"meta":"m","items":[]}`,
+	)
+
+	tests := []struct {
+		name     string
+		doc      string
+		opts     *jsonast.ConformOptions
+		expected []string
+	}{
+		{
+			name:     "ok",
+			doc:      `{"id":3,"name":"b","tags":["y","z"],"meta":{"any":[1]},"opt":false,"items":[{"n":2}]}`,
+			expected: []string{},
+		},
+		{
+			name:     "ok with omitted and null",
+			doc:      `{"id":3,"name":null,"tags":[],"meta":null,"items":[]}`,
+			expected: []string{},
+		},
+		{
+			name: "violations",
+			doc: `{"id":"3","tags":[1,"y",null],"meta":1,"opt":null,
+"items":[{"n":1},{},{"n":1,"x/y":2}],"extra":true}`,
+			expected: []string{
+				`type mismatch: doc.json:1:7: /id: expected number, got string`,
+				`missing key: doc.json:1:1: /name: missing required key "name"`,
+				`type mismatch: doc.json:1:19: /tags/0: expected string, got number`,
+				`unexpected null: doc.json:1:25: /tags/2: expected string, got null`,
+				`unexpected null: doc.json:1:46: /opt: expected boolean, got null`,
+				`missing key: doc.json:2:18: /items/1/n: missing required key "n"`,
+			},
+		},
+		{
+			name: "unknown keys",
+			doc: `{"id":3,"name":"b","tags":[],"meta":1,
+"items":[{"n":1,"x/y":2}],"extra":true}`,
+			opts: &jsonast.ConformOptions{DisallowUnknownKeys: true},
+			expected: []string{
+				`unknown key: doc.json:2:17: /items/0/x~1y: unknown key "x/y"`,
+				`unknown key: doc.json:2:27: /extra: unknown key "extra"`,
+			},
+		},
+		{
+			name:     "root mismatch",
+			doc:      `[]`,
+			expected: []string{`type mismatch: doc.json:1:1: : expected object, got array`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := jsonast.ParseBytes("doc.json", []byte(tt.doc))
+			require.NoError(t, err)
+
+			errs := []string{}
+
+			for _, v := range jsonast.Conforms(doc, shape, tt.opts) {
+				errs = append(errs, v.Kind.String()+": "+v.Error())
+			}
+
+			assert.Equal(t, tt.expected, errs)
+		})
+	}
+}
+
+func TestConforms_Concurrent(t *testing.T) {
+	fields := []string{}
+
+	for i := range 20 {
+		fields = append(fields, fmt.Sprintf(`"k%d":%d`, i, i))
+	}
+
+	src := "{" + strings.Join(fields, ",") + "}"
+	shape := inferShape(t, src)
+	shape.Object.BuildIndex()
+	doc, err := jsonast.ParseBytes("doc.json", []byte(src))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	counts := make([]int, 4)
+
+	for i := range counts {
+		wg.Go(func() {
+			for range 10 {
+				counts[i] += len(jsonast.Conforms(doc, shape, &jsonast.ConformOptions{DisallowUnknownKeys: true}))
+			}
+		})
+	}
+
+	wg.Wait()
+	assert.Equal(t, []int{0, 0, 0, 0}, counts)
+}
@@ -0,0 +1,52 @@
+package jsonast
+
+// ValueSyntax holds the source text around a value that ParseLossless preserves.
+type ValueSyntax struct {
+	Leading  string
+	Commas   []string
+	Close    string
+	Trailing string
+}
+
+// MemberSyntax holds the source text around an object key that ParseLossless preserves.
+type MemberSyntax struct {
+	Leading string
+	Colon   string
+}
+
+// ParseLossless parses src keeping the whitespace around every node,
+// so that WriteTo reproduces src byte for byte until the tree is edited.
+func ParseLossless(filename string, src []byte) (*JsonValue, error) {
+	p := &tolerantParser{scanner: newScanner(filename, src), lossless: true}
+	v := p.parseDocument()
+
+	if len(p.diags) > 0 {
+		return nil, p.diags[0]
+	}
+
+	v.Syntax.Trailing = p.tok.leading
+	return v, nil
+}
+
+// Replace swaps the value at ptr for nv, keeping the whitespace that preceded the old value.
+func (v *JsonValue) Replace(ptr string, nv *JsonValue) error {
+	old, err := v.Lookup(ptr)
+
+	if err != nil {
+		return err
+	}
+
+	if old.Syntax != nil {
+		syn := &ValueSyntax{Leading: old.Syntax.Leading, Trailing: old.Syntax.Trailing}
+
+		if nv.Syntax != nil {
+			syn.Commas = nv.Syntax.Commas
+			syn.Close = nv.Syntax.Close
+		}
+
+		nv.Syntax = syn
+	}
+
+	*old = *nv
+	return nil
+}
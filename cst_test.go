@@ -0,0 +1,109 @@
+package jsonast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+const cstJSON = "\r\n{\n  \"name\" : \"caf\\u00e9\",\t\"path\": \"a\\/b\",\n  \"nums\": [ 1 , 2.50,-3e+2 ],\n  \"empty\": { }, \"ary\": [\n  ],\n  \"nested\": {\"t\": true, \"f\": false, \"n\": null}\n}\n\n"
+
+func TestParseLossless_RoundTrip(t *testing.T) {
+	tests := []string{
+		cstJSON,
+		`1`,
+		` "😀" `,
+		"[\n]",
+		`{"a":{"b":[{}]}}`,
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			v, err := jsonast.ParseLossless("<filename>", []byte(src))
+			require.NoError(t, err)
+			buf := &bytes.Buffer{}
+			n, err := v.WriteTo(buf)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(src)), n)
+			assert.Equal(t, src, buf.String())
+		})
+	}
+}
+
+func TestParseLossless_SameTree(t *testing.T) {
+	v, err := jsonast.ParseLossless("<filename>", []byte(cstJSON))
+	require.NoError(t, err)
+	expected, err := jsonast.ParseBytes("<filename>", []byte(cstJSON))
+	require.NoError(t, err)
+	assert.Equal(t, expected, stripSyntax(v))
+}
+
+func TestParseLossless_Err(t *testing.T) {
+	_, err := jsonast.ParseLossless("<filename>", []byte(`{"a": [1,]}`))
+	assert.EqualError(t, err, `<filename>:1:10: unexpected token "]" (expected value)`)
+	_, err = jsonast.ParseLossless("<filename>", []byte(`{} {}`))
+	assert.EqualError(t, err, `<filename>:1:4: unexpected token "{"`)
+}
+
+func TestParseLossless_Edit(t *testing.T) {
+	v, err := jsonast.ParseLossless("<filename>", []byte(cstJSON))
+	require.NoError(t, err)
+
+	nums, err := v.Lookup("/nums/1")
+	require.NoError(t, err)
+	nums.Number.Text = "7"
+
+	name, err := v.Lookup("/name")
+	require.NoError(t, err)
+	name.String.Text = "cafe"
+
+	require.NoError(t, v.Replace("/nested/t", &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+		{Number: vnum("1")},
+		{String: vstr("x\ny")},
+	}}}))
+
+	v.Object.Members[3].Value.Object.Members = append(v.Object.Members[3].Value.Object.Members, &jsonast.JsonObjectMember{
+		Key:   "k",
+		Value: &jsonast.JsonValue{Null: vnull()},
+	})
+
+	buf := &bytes.Buffer{}
+	_, err = v.WriteTo(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "\r\n{\n  \"name\" : \"cafe\",\t\"path\": \"a\\/b\",\n  \"nums\": [ 1 , 7,-3e+2 ],\n  \"empty\": {\"k\":null }, \"ary\": [\n  ],\n  \"nested\": {\"t\": [1,\"x\\ny\"], \"f\": false, \"n\": null}\n}\n\n", buf.String())
+
+	require.NoError(t, v.Replace("", &jsonast.JsonValue{True: vtrue()}))
+	buf.Reset()
+	_, err = v.WriteTo(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "\r\ntrue\n\n", buf.String())
+
+	assert.ErrorContains(t, v.Replace("/x", &jsonast.JsonValue{True: vtrue()}), "cannot index into a scalar")
+}
+
+func TestMarshalJSON(t *testing.T) {
+	v, err := jsonast.ParseBytes("<filename>", []byte(cstJSON))
+	require.NoError(t, err)
+	b, err := v.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"caf\u00e9","path":"a\/b","nums":[1,2.50,-3e+2],"empty":{},"ary":[],"nested":{"t":true,"f":false,"n":null}}`, string(b))
+
+	b, err = stripSource(v).MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"café","path":"a/b","nums":[1,2.50,-3e+2],"empty":{},"ary":[],"nested":{"t":true,"f":false,"n":null}}`, string(b))
+
+	b, err = json.Marshal(map[string]*jsonast.JsonValue{"v": {String: vstr("\"\\\x01\t")}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"v":"\"\\\u0001\t"}`, string(b))
+
+	_, err = (&jsonast.JsonValue{}).MarshalJSON()
+	assert.EqualError(t, err, "cannot serialize an empty value")
+
+	v, _ = jsonast.ParseTolerant("<filename>", []byte(`[1, ?]`))
+	_, err = v.MarshalJSON()
+	assert.EqualError(t, err, `<filename>:1:5: invalid character '?'`)
+}
@@ -0,0 +1,263 @@
+package jsonast
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type ChangeOp int
+
+const (
+	ChangeAdded ChangeOp = iota
+	ChangeRemoved
+	ChangeReplaced
+	ChangeMoved
+)
+
+func (op ChangeOp) String() string {
+	switch op {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeReplaced:
+		return "replaced"
+	case ChangeMoved:
+		return "moved"
+	default:
+		return fmt.Sprintf("ChangeOp(%d)", int(op))
+	}
+}
+
+// Change describes one difference between two trees.
+// Old* fields refer to the first tree and are unset for ChangeAdded;
+// New* fields refer to the second tree and are unset for ChangeRemoved.
+type Change struct {
+	Op       ChangeOp
+	OldPath  string
+	NewPath  string
+	OldValue *JsonValue
+	NewValue *JsonValue
+	OldPos   lexer.Position
+	NewPos   lexer.Position
+}
+
+type ArrayDiffStrategy int
+
+const (
+	ArrayDiffIndex ArrayDiffStrategy = iota
+	ArrayDiffLCS
+)
+
+type NumberComparison int
+
+const (
+	NumberCompareText NumberComparison = iota
+	NumberCompareNumeric
+)
+
+type DiffOptions struct {
+	Arrays  ArrayDiffStrategy
+	Numbers NumberComparison
+}
+
+func Diff(a, b *JsonValue) []*Change {
+	return DiffWithOptions(a, b, nil)
+}
+
+func DiffWithOptions(a, b *JsonValue, opts *DiffOptions) []*Change {
+	if opts == nil {
+		opts = &DiffOptions{}
+	}
+
+	d := &differ{opts: opts, changes: []*Change{}}
+	d.diff(a, b, "", "")
+	d.pairMoves()
+	return d.changes
+}
+
+type differ struct {
+	opts    *DiffOptions
+	changes []*Change
+}
+
+func (d *differ) equal(a, b *JsonValue) bool {
+	return Equal(a, b, &EqualOptions{Numbers: d.opts.Numbers})
+}
+
+func (d *differ) added(path string, v *JsonValue) {
+	d.changes = append(d.changes, &Change{Op: ChangeAdded, NewPath: path, NewValue: v, NewPos: v.Pos})
+}
+
+func (d *differ) removed(path string, v *JsonValue) {
+	d.changes = append(d.changes, &Change{Op: ChangeRemoved, OldPath: path, OldValue: v, OldPos: v.Pos})
+}
+
+func (d *differ) diff(a, b *JsonValue, pathA, pathB string) {
+	switch {
+	case a.IsObject() && b.IsObject():
+		d.diffObjects(a.Object, b.Object, pathA, pathB)
+	case a.IsArray() && b.IsArray():
+		if d.opts.Arrays == ArrayDiffLCS {
+			d.diffArraysLCS(a.Array.Elements, b.Array.Elements, pathA, pathB)
+		} else {
+			d.diffArraysIndex(a.Array.Elements, b.Array.Elements, pathA, pathB)
+		}
+	case !d.equal(a, b):
+		d.changes = append(d.changes, &Change{
+			Op:       ChangeReplaced,
+			OldPath:  pathA,
+			NewPath:  pathB,
+			OldValue: a,
+			NewValue: b,
+			OldPos:   a.Pos,
+			NewPos:   b.Pos,
+		})
+	}
+}
+
+func (d *differ) diffObjects(a, b *JsonObject, pathA, pathB string) {
+	ai, bi := a.lastIndexes(), b.lastIndexes()
+
+	for i, m := range a.Members {
+		if ai[m.Key] != i {
+			continue
+		}
+
+		if j, ok := bi[m.Key]; ok {
+			d.diff(m.Value, b.Members[j].Value, appendPointer(pathA, m.Key), appendPointer(pathB, m.Key))
+		} else {
+			d.removed(appendPointer(pathA, m.Key), m.Value)
+		}
+	}
+
+	for i, m := range b.Members {
+		if bi[m.Key] != i {
+			continue
+		}
+
+		if _, ok := ai[m.Key]; !ok {
+			d.added(appendPointer(pathB, m.Key), m.Value)
+		}
+	}
+}
+
+func (d *differ) diffArraysIndex(a, b []*JsonValue, pathA, pathB string) {
+	for i := range max(len(a), len(b)) {
+		switch {
+		case i >= len(b):
+			d.removed(appendIndexPointer(pathA, i), a[i])
+		case i >= len(a):
+			d.added(appendIndexPointer(pathB, i), b[i])
+		default:
+			d.diff(a[i], b[i], appendIndexPointer(pathA, i), appendIndexPointer(pathB, i))
+		}
+	}
+}
+
+func (d *differ) diffArraysLCS(a, b []*JsonValue, pathA, pathB string) {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if d.equal(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	gapA, gapB := 0, 0
+
+	flush := func() {
+		for k := range max(i-gapA, j-gapB) {
+			ai, bj := gapA+k, gapB+k
+
+			switch {
+			case ai >= i:
+				d.added(appendIndexPointer(pathB, bj), b[bj])
+			case bj >= j:
+				d.removed(appendIndexPointer(pathA, ai), a[ai])
+			default:
+				d.diff(a[ai], b[bj], appendIndexPointer(pathA, ai), appendIndexPointer(pathB, bj))
+			}
+		}
+	}
+
+	for i < len(a) && j < len(b) {
+		if d.equal(a[i], b[j]) {
+			flush()
+			i++
+			j++
+			gapA, gapB = i, j
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	i, j = len(a), len(b)
+	flush()
+}
+
+// pairMoves turns a removal and an addition of equal values into a single move.
+// Additions are bucketed by Hash so that each removal is only compared with likely matches.
+func (d *differ) pairMoves() {
+	opts := &EqualOptions{Numbers: d.opts.Numbers}
+	buckets := make(map[uint64][]*Change)
+
+	for _, a := range d.changes {
+		if a.Op == ChangeAdded {
+			h := Hash(a.NewValue, opts)
+			buckets[h] = append(buckets[h], a)
+		}
+	}
+
+	paired := make(map[*Change]bool)
+
+	for _, r := range d.changes {
+		if r.Op != ChangeRemoved {
+			continue
+		}
+
+		h := Hash(r.OldValue, opts)
+		bucket := buckets[h]
+
+		for k, a := range bucket {
+			if d.equal(r.OldValue, a.NewValue) {
+				paired[a] = true
+				if k == 0 {
+					buckets[h] = bucket[1:]
+				} else {
+					buckets[h] = slices.Delete(bucket, k, k+1)
+				}
+
+				r.Op = ChangeMoved
+				r.NewPath = a.NewPath
+				r.NewValue = a.NewValue
+				r.NewPos = a.NewPos
+				break
+			}
+		}
+	}
+
+	changes := d.changes[:0]
+
+	for _, c := range d.changes {
+		if !paired[c] {
+			changes = append(changes, c)
+		}
+	}
+
+	d.changes = changes
+}
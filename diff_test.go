@@ -0,0 +1,183 @@
+package jsonast_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+type change struct {
+	Op      jsonast.ChangeOp
+	OldPath string
+	NewPath string
+	Old     string
+	New     string
+}
+
+func summarize(t *testing.T, changes []*jsonast.Change) []change {
+	t.Helper()
+	summary := []change{}
+
+	for _, c := range changes {
+		s := change{Op: c.Op, OldPath: c.OldPath, NewPath: c.NewPath}
+
+		if c.OldValue != nil {
+			b, err := stripSource(c.OldValue).MarshalJSON()
+			require.NoError(t, err)
+			s.Old = string(b)
+		}
+
+		if c.NewValue != nil {
+			b, err := stripSource(c.NewValue).MarshalJSON()
+			require.NoError(t, err)
+			s.New = string(b)
+		}
+
+		summary = append(summary, s)
+	}
+
+	return summary
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		opts     *jsonast.DiffOptions
+		expected []change
+	}{
+		{
+			name:     "equal",
+			a:        `{"a":[1,{"b":null}],"c":"s"}`,
+			b:        `{"c":"s","a":[1,{"b":null}]}`,
+			expected: []change{},
+		},
+		{
+			name: "scalars",
+			a:    `{"s":"x","n":1,"t":true,"k":null,"f":1.0}`,
+			b:    `{"s":"y","n":1,"t":false,"k":{},"f":1}`,
+			expected: []change{
+				{jsonast.ChangeReplaced, "/s", "/s", `"x"`, `"y"`},
+				{jsonast.ChangeReplaced, "/t", "/t", `true`, `false`},
+				{jsonast.ChangeReplaced, "/k", "/k", `null`, `{}`},
+				{jsonast.ChangeReplaced, "/f", "/f", `1.0`, `1`},
+			},
+		},
+		{
+			name:     "numeric",
+			a:        `{"f":1.0,"g":[15e-1],"h":-0,"i":100}`,
+			b:        `{"f":1,"g":[1.50],"h":0.0,"i":1e3}`,
+			opts:     &jsonast.DiffOptions{Numbers: jsonast.NumberCompareNumeric},
+			expected: []change{{jsonast.ChangeReplaced, "/i", "/i", `100`, `1e3`}},
+		},
+		{
+			name: "added removed moved",
+			a:    `{"a":1,"b":{"x":[1,2]},"c":"gone"}`,
+			b:    `{"a":1,"d":{"x":[1,2]},"e/f":true}`,
+			expected: []change{
+				{jsonast.ChangeMoved, "/b", "/d", `{"x":[1,2]}`, `{"x":[1,2]}`},
+				{jsonast.ChangeRemoved, "/c", "", `"gone"`, ``},
+				{jsonast.ChangeAdded, "", "/e~1f", ``, `true`},
+			},
+		},
+		{
+			name: "array index",
+			a:    `[1,2,3,{"a":1}]`,
+			b:    `[0,1,2,3,{"a":2}]`,
+			expected: []change{
+				{jsonast.ChangeReplaced, "/0", "/0", `1`, `0`},
+				{jsonast.ChangeReplaced, "/1", "/1", `2`, `1`},
+				{jsonast.ChangeReplaced, "/2", "/2", `3`, `2`},
+				{jsonast.ChangeReplaced, "/3", "/3", `{"a":1}`, `3`},
+				{jsonast.ChangeAdded, "", "/4", ``, `{"a":2}`},
+			},
+		},
+		{
+			name: "array lcs",
+			a:    `[1,2,3,{"a":1}]`,
+			b:    `[0,1,2,3,{"a":2}]`,
+			opts: &jsonast.DiffOptions{Arrays: jsonast.ArrayDiffLCS},
+			expected: []change{
+				{jsonast.ChangeAdded, "", "/0", ``, `0`},
+				{jsonast.ChangeReplaced, "/3/a", "/4/a", `1`, `2`},
+			},
+		},
+		{
+			name: "array lcs gaps",
+			a:    `["a","x","y","b","z"]`,
+			b:    `["a","q","b"]`,
+			opts: &jsonast.DiffOptions{Arrays: jsonast.ArrayDiffLCS},
+			expected: []change{
+				{jsonast.ChangeReplaced, "/1", "/1", `"x"`, `"q"`},
+				{jsonast.ChangeRemoved, "/2", "", `"y"`, ``},
+				{jsonast.ChangeRemoved, "/4", "", `"z"`, ``},
+			},
+		},
+		{
+			name: "array lcs move",
+			a:    `[{"id":1},"m",{"id":2}]`,
+			b:    `[{"id":1},{"id":2},"m"]`,
+			opts: &jsonast.DiffOptions{Arrays: jsonast.ArrayDiffLCS},
+			expected: []change{
+				{jsonast.ChangeMoved, "/1", "/2", `"m"`, `"m"`},
+			},
+		},
+		{
+			name: "root replaced",
+			a:    `[1]`,
+			b:    `{"0":1}`,
+			expected: []change{
+				{jsonast.ChangeReplaced, "", "", `[1]`, `{"0":1}`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := jsonast.ParseBytes("a.json", []byte(tt.a))
+			require.NoError(t, err)
+			b, err := jsonast.ParseBytes("b.json", []byte(tt.b))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, summarize(t, jsonast.DiffWithOptions(a, b, tt.opts)))
+		})
+	}
+}
+
+func TestDiff_Pos(t *testing.T) {
+	a, err := jsonast.ParseBytes("a.json", []byte("{\n  \"a\": 1,\n  \"b\": 2\n}"))
+	require.NoError(t, err)
+	b, err := jsonast.ParseBytes("b.json", []byte(`{"a": 3, "c": 4}`))
+	require.NoError(t, err)
+
+	changes := jsonast.Diff(a, b)
+	require.Len(t, changes, 3)
+	assert.Equal(t, "a.json:2:8", changes[0].OldPos.String())
+	assert.Equal(t, "b.json:1:7", changes[0].NewPos.String())
+	assert.Equal(t, "a.json:3:8", changes[1].OldPos.String())
+	assert.Equal(t, "b.json:1:15", changes[2].NewPos.String())
+}
+
+func TestDiff_LargeObject(t *testing.T) {
+	const n = 40000
+	a := jsonast.NewObject()
+	b := jsonast.NewObject()
+
+	for i := range n {
+		a.Object.Members = append(a.Object.Members, jsonast.NewMember("a"+strconv.Itoa(i), jsonast.NewNumberFromInt(int64(i%10))))
+		b.Object.Members = append(b.Object.Members, jsonast.NewMember("b"+strconv.Itoa(i), jsonast.NewNumberFromInt(int64(i%10))))
+	}
+
+	assert.Empty(t, jsonast.Diff(a, a))
+
+	changes := jsonast.Diff(a, b)
+	require.Len(t, changes, n)
+
+	for _, c := range changes {
+		assert.Equal(t, jsonast.ChangeMoved, c.Op)
+		assert.Equal(t, c.OldPath[2:], c.NewPath[2:])
+	}
+}
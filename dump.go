@@ -0,0 +1,127 @@
+package jsonast
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type DumpOptions struct {
+	Positions bool
+}
+
+// Dump writes v as an indented tree, one node per line.
+// Nullable scalars are marked "nullable", any-type nulls "any",
+// and omittable object keys are suffixed with "?".
+func (v *JsonValue) Dump(w io.Writer, opts *DumpOptions) error {
+	if opts == nil {
+		opts = &DumpOptions{}
+	}
+
+	_, err := io.WriteString(w, v.dump(opts))
+	return err
+}
+
+func (v *JsonValue) dump(opts *DumpOptions) string {
+	b := &strings.Builder{}
+	v.appendDump(b, "", "", opts)
+	return b.String()
+}
+
+func (v *JsonValue) appendDump(b *strings.Builder, indent string, label string, opts *DumpOptions) {
+	b.WriteString(indent)
+	b.WriteString(label)
+
+	switch o := v.Value().(type) {
+	case *JsonFalse:
+		b.WriteString("false")
+		writeNullable(b, o.Nullable())
+	case *JsonNull:
+		b.WriteString("null")
+
+		if o.any {
+			b.WriteString(" any")
+		}
+	case *JsonTrue:
+		b.WriteString("true")
+		writeNullable(b, o.Nullable())
+	case *JsonNumber:
+		b.WriteString("number ")
+		b.WriteString(o.Text)
+		writeNullable(b, o.Nullable())
+	case *JsonString:
+		b.WriteString("string ")
+		b.WriteString(quote(o.Text))
+		writeNullable(b, o.Nullable())
+	case *JsonObject:
+		b.WriteString("object")
+	case *JsonArray:
+		b.WriteString("array")
+	case *JsonInvalid:
+		b.WriteString("invalid")
+
+		if o.Err != nil {
+			b.WriteString(" ")
+			b.WriteString(strconv.Quote(o.Err.Msg))
+		}
+	default:
+		b.WriteString("empty")
+	}
+
+	if opts.Positions && v.Pos.Line > 0 {
+		b.WriteString(" @")
+		b.WriteString(v.Pos.String())
+	}
+
+	b.WriteString("\n")
+
+	switch {
+	case v.Object != nil:
+		for _, m := range v.Object.Members {
+			label := quote(m.Key)
+
+			if v.Object.isOmittable(m.Key) {
+				label += "?"
+			}
+
+			m.Value.appendDump(b, indent+"  ", label+": ", opts)
+		}
+	case v.Array != nil:
+		for i, e := range v.Array.Elements {
+			e.appendDump(b, indent+"  ", fmt.Sprintf("[%d]: ", i), opts)
+		}
+	}
+}
+
+func writeNullable(b *strings.Builder, nullable bool) {
+	if nullable {
+		b.WriteString(" nullable")
+	}
+}
+
+type plainValue JsonValue
+
+// Format implements fmt.Formatter.
+// %v and %s print compact JSON, %+v prints the Dump tree with positions,
+// and %#v prints the Go struct as usual.
+func (v *JsonValue) Format(f fmt.State, verb rune) {
+	switch {
+	case v == nil:
+		io.WriteString(f, "<nil>") //nolint:errcheck
+	case verb == 'v' && f.Flag('#'):
+		// plainValue drops this method to avoid recursion; put the real type name back.
+		s := strings.TrimPrefix(fmt.Sprintf("%#v", (*plainValue)(v)), "&jsonast.plainValue")
+		io.WriteString(f, "&jsonast.JsonValue"+s) //nolint:errcheck
+	case verb == 'v' && f.Flag('+'):
+		io.WriteString(f, v.dump(&DumpOptions{Positions: true})) //nolint:errcheck
+	case verb == 'v' || verb == 's':
+		if b, err := v.MarshalJSON(); err == nil {
+			f.Write(b) //nolint:errcheck
+		} else {
+			io.WriteString(f, strings.TrimSuffix(v.dump(&DumpOptions{}), "\n")) //nolint:errcheck
+		}
+	default:
+		fmt.Fprintf(f, "%%!%c(*jsonast.JsonValue)", verb)
+	}
+}
@@ -0,0 +1,65 @@
+package jsonast_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestDump(t *testing.T) {
+	a, err := jsonast.ParseBytes("a.json", []byte(`{"s":"x","n":[1,null],"o":{"t":true},"k":1}`))
+	require.NoError(t, err)
+	b, err := jsonast.ParseBytes("b.json", []byte(`{"s":null,"n":[2],"o":{"t":false,"f":false},"k":"s","m":[1,"s"]}`))
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, a.UnionType(b).Dump(buf, nil))
+	assert.Equal(t, `object
+  "s": string "x" nullable
+  "n": array
+    [0]: number 1 nullable
+  "o": object
+    "t": true
+    "f"?: false
+  "k": null any
+  "m"?: array
+    [0]: number 1
+    [1]: string "s"
+`, buf.String())
+}
+
+func TestDump_Positions(t *testing.T) {
+	v, diags := jsonast.ParseTolerant("a.json", []byte("[\n  \"\\u00e9\",\n  x\n]"))
+	require.Len(t, diags, 1)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, v.Dump(buf, &jsonast.DumpOptions{Positions: true}))
+	assert.Equal(t, `array @a.json:1:1
+  [0]: string "é" @a.json:2:3
+  [1]: invalid "invalid literal \"x\"" @a.json:3:3
+`, buf.String())
+}
+
+func TestFormat(t *testing.T) {
+	v, err := jsonast.ParseBytes("a.json", []byte(`{"a": [1, "b"]}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"a":[1,"b"]}`, fmt.Sprintf("%v", v))
+	assert.Equal(t, `{"a":[1,"b"]}`, fmt.Sprintf("%s", v))
+	assert.Equal(t, `object @a.json:1:1
+  "a": array @a.json:1:7
+    [0]: number 1 @a.json:1:8
+    [1]: string "b" @a.json:1:11
+`, fmt.Sprintf("%+v", v))
+	assert.True(t, strings.HasPrefix(fmt.Sprintf("%#v", v), "&jsonast.JsonValue{"))
+	assert.Contains(t, fmt.Sprintf("%#v", v), "Object:(*jsonast.JsonObject)")
+	assert.NotContains(t, fmt.Sprintf("%#v", v), "plainValue")
+	assert.Equal(t, "%!d(*jsonast.JsonValue)", fmt.Sprintf("%d", v))
+	assert.Equal(t, "empty", fmt.Sprintf("%v", &jsonast.JsonValue{}))
+	assert.Equal(t, "<nil>", fmt.Sprintf("%v", (*jsonast.JsonValue)(nil)))
+}
@@ -0,0 +1,95 @@
+package jsonast
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type DuplicateKeyError struct {
+	Key    string
+	First  lexer.Position
+	Second lexer.Position
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("%s: duplicate key %q (first defined at %s)", e.Second, e.Key, e.First)
+}
+
+func FindDuplicateKeys(v *JsonValue) []*DuplicateKeyError {
+	dups := []*DuplicateKeyError{}
+	findDuplicateKeys(v, &dups)
+	return dups
+}
+
+func findDuplicateKeys(v *JsonValue, dups *[]*DuplicateKeyError) {
+	if v.IsObject() {
+		seen := make(map[string]*JsonObjectMember, len(v.Object.Members))
+
+		for _, m := range v.Object.Members {
+			if first, ok := seen[m.Key]; ok {
+				*dups = append(*dups, &DuplicateKeyError{Key: m.Key, First: first.Pos, Second: m.Pos})
+			} else {
+				seen[m.Key] = m
+			}
+
+			findDuplicateKeys(m.Value, dups)
+		}
+	} else if v.IsArray() {
+		for _, e := range v.Array.Elements {
+			findDuplicateKeys(e, dups)
+		}
+	}
+}
+
+func applyDuplicateKeyPolicy(v *JsonValue, policy DuplicateKeyPolicy) error {
+	switch policy {
+	case DuplicateKeysKeepAll:
+		return nil
+	case DuplicateKeysError:
+		if dups := FindDuplicateKeys(v); len(dups) > 0 {
+			return dups[0]
+		}
+
+		return nil
+	case DuplicateKeysKeepFirst, DuplicateKeysKeepLast:
+		dedupKeys(v, policy == DuplicateKeysKeepLast)
+		return nil
+	default:
+		return fmt.Errorf("unknown duplicate key policy: %d", policy)
+	}
+}
+
+func dedupKeys(v *JsonValue, keepLast bool) {
+	if v.IsObject() {
+		last := make(map[string]int, len(v.Object.Members))
+
+		for i, m := range v.Object.Members {
+			last[m.Key] = i
+		}
+
+		if len(last) < len(v.Object.Members) {
+			members := make([]*JsonObjectMember, 0, len(last))
+			seen := make(map[string]struct{}, len(last))
+
+			for i, m := range v.Object.Members {
+				if _, ok := seen[m.Key]; ok || (keepLast && last[m.Key] != i) {
+					continue
+				}
+
+				seen[m.Key] = struct{}{}
+				members = append(members, m)
+			}
+
+			v.Object.Members = members
+		}
+
+		for _, m := range v.Object.Members {
+			dedupKeys(m.Value, keepLast)
+		}
+	} else if v.IsArray() {
+		for _, e := range v.Array.Elements {
+			dedupKeys(e, keepLast)
+		}
+	}
+}
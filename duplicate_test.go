@@ -0,0 +1,113 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+const dupJSON = `{"a":1,"b":{"x":true,"x":false},"a":2,"c":[{"y":null,"y":"s"}],"a":3}`
+
+func TestFindDuplicateKeys(t *testing.T) {
+	v, err := jsonast.ParseBytes("<filename>", []byte(dupJSON))
+	require.NoError(t, err)
+
+	dups := jsonast.FindDuplicateKeys(v)
+	require.Len(t, dups, 4)
+	assert.Equal(t, &jsonast.DuplicateKeyError{
+		Key:    "x",
+		First:  lexer.Position{Filename: "<filename>", Offset: 12, Line: 1, Column: 13},
+		Second: lexer.Position{Filename: "<filename>", Offset: 21, Line: 1, Column: 22},
+	}, dups[0])
+	assert.Equal(t, "a", dups[1].Key)
+	assert.Equal(t, 2, dups[1].First.Column)
+	assert.Equal(t, "y", dups[2].Key)
+	assert.Equal(t, "a", dups[3].Key)
+	assert.EqualError(t, dups[0], `<filename>:1:22: duplicate key "x" (first defined at <filename>:1:13)`)
+
+	v, err = jsonast.ParseBytes("<filename>", []byte(`{"a":1,"b":[{"a":1}]}`))
+	require.NoError(t, err)
+	assert.Empty(t, jsonast.FindDuplicateKeys(v))
+}
+
+func TestParseWithOptions_DuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   jsonast.DuplicateKeyPolicy
+		expected *jsonast.JsonValue
+	}{
+		{
+			name:   "keep all",
+			policy: jsonast.DuplicateKeysKeepAll,
+			expected: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+				{Key: "a", Value: &jsonast.JsonValue{Number: vnum("1")}},
+				{Key: "b", Value: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+					{Key: "x", Value: &jsonast.JsonValue{True: vtrue()}},
+					{Key: "x", Value: &jsonast.JsonValue{False: vfalse()}},
+				}}}},
+				{Key: "a", Value: &jsonast.JsonValue{Number: vnum("2")}},
+				{Key: "c", Value: &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+					{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+						{Key: "y", Value: &jsonast.JsonValue{Null: vnull()}},
+						{Key: "y", Value: &jsonast.JsonValue{String: vstr("s")}},
+					}}},
+				}}}},
+				{Key: "a", Value: &jsonast.JsonValue{Number: vnum("3")}},
+			}}},
+		},
+		{
+			name:   "keep first",
+			policy: jsonast.DuplicateKeysKeepFirst,
+			expected: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+				{Key: "a", Value: &jsonast.JsonValue{Number: vnum("1")}},
+				{Key: "b", Value: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+					{Key: "x", Value: &jsonast.JsonValue{True: vtrue()}},
+				}}}},
+				{Key: "c", Value: &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+					{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+						{Key: "y", Value: &jsonast.JsonValue{Null: vnull()}},
+					}}},
+				}}}},
+			}}},
+		},
+		{
+			name:   "keep last",
+			policy: jsonast.DuplicateKeysKeepLast,
+			expected: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+				{Key: "b", Value: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+					{Key: "x", Value: &jsonast.JsonValue{False: vfalse()}},
+				}}}},
+				{Key: "c", Value: &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+					{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+						{Key: "y", Value: &jsonast.JsonValue{String: vstr("s")}},
+					}}},
+				}}}},
+				{Key: "a", Value: &jsonast.JsonValue{Number: vnum("3")}},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := jsonast.ParseBytesWithOptions("<filename>", []byte(dupJSON), &jsonast.ParseOptions{DuplicateKeys: tt.policy})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stripSource(v))
+		})
+	}
+}
+
+func TestParseWithOptions_DuplicateKeysError(t *testing.T) {
+	_, err := jsonast.ParseBytesWithOptions("<filename>", []byte(dupJSON), &jsonast.ParseOptions{DuplicateKeys: jsonast.DuplicateKeysError})
+	var dupErr *jsonast.DuplicateKeyError
+	require.ErrorAs(t, err, &dupErr)
+	assert.Equal(t, "x", dupErr.Key)
+	assert.Equal(t, 13, dupErr.First.Column)
+	assert.Equal(t, 22, dupErr.Second.Column)
+
+	v, err := jsonast.ParseBytesWithOptions("<filename>", []byte(`{"a":1}`), &jsonast.ParseOptions{DuplicateKeys: jsonast.DuplicateKeysError})
+	require.NoError(t, err)
+	assert.Len(t, v.Object.Members, 1)
+}
@@ -0,0 +1,183 @@
+package jsonast
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// WriteTo serializes v as JSON.
+// Strings keep their original escapes while Raw still matches Text.
+// Nodes parsed with ParseLossless are written back with their original whitespace;
+// nodes without syntax information are written compactly.
+func (v *JsonValue) WriteTo(w io.Writer) (int64, error) {
+	buf, err := v.appendJSON(nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if v.Syntax != nil {
+		buf = append(buf, v.Syntax.Trailing...)
+	}
+
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+func (v *JsonValue) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	if _, err := v.WriteTo(buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (v *JsonValue) appendJSON(buf []byte) ([]byte, error) {
+	syn := v.Syntax
+
+	if syn == nil {
+		syn = &ValueSyntax{}
+	}
+
+	buf = append(buf, syn.Leading...)
+
+	switch o := v.Value().(type) {
+	case *JsonFalse:
+		buf = append(buf, "false"...)
+	case *JsonNull:
+		buf = append(buf, "null"...)
+	case *JsonTrue:
+		buf = append(buf, "true"...)
+	case *JsonNumber:
+		buf = append(buf, o.Text...)
+	case *JsonString:
+		buf = appendRawString(buf, o.Raw, o.Text)
+	case *JsonObject:
+		buf = append(buf, '{')
+
+		for i, m := range o.Members {
+			if i > 0 {
+				buf = appendComma(buf, syn.Commas, i-1)
+			}
+
+			msyn := m.Syntax
+
+			if msyn == nil {
+				msyn = &MemberSyntax{}
+			}
+
+			buf = append(buf, msyn.Leading...)
+			buf = appendRawString(buf, m.RawKey, m.Key)
+			buf = append(buf, msyn.Colon...)
+			buf = append(buf, ':')
+			var err error
+
+			if buf, err = m.Value.appendJSON(buf); err != nil {
+				return nil, err
+			}
+		}
+
+		buf = append(buf, syn.Close...)
+		buf = append(buf, '}')
+	case *JsonArray:
+		buf = append(buf, '[')
+
+		for i, e := range o.Elements {
+			if i > 0 {
+				buf = appendComma(buf, syn.Commas, i-1)
+			}
+
+			var err error
+
+			if buf, err = e.appendJSON(buf); err != nil {
+				return nil, err
+			}
+		}
+
+		buf = append(buf, syn.Close...)
+		buf = append(buf, ']')
+	case *JsonInvalid:
+		if o.Err != nil {
+			return nil, o.Err
+		}
+
+		return nil, errors.New("cannot serialize an invalid value")
+	default:
+		return nil, errors.New("cannot serialize an empty value")
+	}
+
+	return buf, nil
+}
+
+func appendComma(buf []byte, commas []string, i int) []byte {
+	if i < len(commas) {
+		buf = append(buf, commas[i]...)
+	}
+
+	return append(buf, ',')
+}
+
+func appendRawString(buf []byte, raw string, text string) []byte {
+	if raw != "" {
+		if s, err := decodeString(raw); err == nil && s == text {
+			return append(buf, raw...)
+		}
+	}
+
+	return appendString(buf, text)
+}
+
+const hex = "0123456789abcdef"
+
+// quote returns s as a JSON string with the minimal escaping allowed by RFC 8259.
+func quote(s string) string {
+	return string(appendString(nil, s))
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf = append(buf, '\\', c)
+			case c == '\b':
+				buf = append(buf, '\\', 'b')
+			case c == '\f':
+				buf = append(buf, '\\', 'f')
+			case c == '\n':
+				buf = append(buf, '\\', 'n')
+			case c == '\r':
+				buf = append(buf, '\\', 'r')
+			case c == '\t':
+				buf = append(buf, '\\', 't')
+			case c < 0x20:
+				buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			default:
+				buf = append(buf, c)
+			}
+
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, "\ufffd"...)
+		} else {
+			buf = append(buf, s[i:i+size]...)
+		}
+
+		i += size
+	}
+
+	return append(buf, '"')
+}
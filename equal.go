@@ -0,0 +1,214 @@
+package jsonast
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+)
+
+type EqualOptions struct {
+	// OrderedMembers makes object member order significant.
+	// By default members are compared by key, and the last of duplicate keys wins.
+	OrderedMembers bool
+	Numbers        NumberComparison
+}
+
+// Equal reports whether a and b hold the same JSON value.
+// Shape flags such as nullability and source information such as positions are ignored.
+func Equal(a, b *JsonValue, opts *EqualOptions) bool {
+	if opts == nil {
+		opts = &EqualOptions{}
+	}
+
+	return equal(a, b, opts)
+}
+
+func equal(a, b *JsonValue, opts *EqualOptions) bool {
+	switch x := a.Value().(type) {
+	case *JsonFalse:
+		return b.IsFalse()
+	case *JsonNull:
+		return b.IsNull()
+	case *JsonTrue:
+		return b.IsTrue()
+	case *JsonNumber:
+		return b.IsNumber() && numberKey(x.Text, opts) == numberKey(b.Number.Text, opts)
+	case *JsonString:
+		return b.IsString() && x.Text == b.String.Text
+	case *JsonObject:
+		if !b.IsObject() {
+			return false
+		} else if opts.OrderedMembers {
+			return equalMembersOrdered(x.Members, b.Object.Members, opts)
+		}
+
+		return equalMembers(x, b.Object, opts)
+	case *JsonArray:
+		if !b.IsArray() || len(x.Elements) != len(b.Array.Elements) {
+			return false
+		}
+
+		for i, e := range x.Elements {
+			if !equal(e, b.Array.Elements[i], opts) {
+				return false
+			}
+		}
+
+		return true
+	case *JsonInvalid:
+		return b.IsInvalid()
+	default:
+		return b.Value() == nil
+	}
+}
+
+func equalMembersOrdered(a, b []*JsonObjectMember, opts *EqualOptions) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, m := range a {
+		if m.Key != b[i].Key || !equal(m.Value, b[i].Value, opts) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalMembers(a, b *JsonObject, opts *EqualOptions) bool {
+	ai, bi := a.lastIndexes(), b.lastIndexes()
+
+	if len(ai) != len(bi) {
+		return false
+	}
+
+	for i, m := range a.Members {
+		if ai[m.Key] != i {
+			continue
+		}
+
+		j, ok := bi[m.Key]
+
+		if !ok || !equal(m.Value, b.Members[j].Value, opts) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func numberKey(text string, opts *EqualOptions) string {
+	if opts.Numbers == NumberCompareNumeric {
+		return normalizeNumber(text)
+	}
+
+	return text
+}
+
+// Hash returns a structural hash of v that is stable across runs and processes.
+// Values that are Equal under the same options hash identically.
+func Hash(v *JsonValue, opts *EqualOptions) uint64 {
+	if opts == nil {
+		opts = &EqualOptions{}
+	}
+
+	s := &hasher{h: fnv.New64a()}
+	return s.value(v, opts)
+}
+
+const (
+	hashFalse byte = iota
+	hashNull
+	hashTrue
+	hashNumber
+	hashString
+	hashObject
+	hashArray
+	hashInvalid
+	hashEmpty
+)
+
+// hasher reuses one FNV-1a state and scratch buffer for every node of a walk.
+type hasher struct {
+	h   hash.Hash64
+	buf []byte
+}
+
+func (s *hasher) sum(buf []byte) uint64 {
+	s.buf = buf
+	s.h.Reset()
+	s.h.Write(buf)
+	return s.h.Sum64()
+}
+
+func (s *hasher) bytes(tag byte, parts ...string) uint64 {
+	buf := append(s.buf[:0], tag)
+
+	for _, p := range parts {
+		// Length-prefix each part so that ("ab", "c") and ("a", "bc") differ.
+		buf = binary.AppendUvarint(buf, uint64(len(p)))
+		buf = append(buf, p...)
+	}
+
+	return s.sum(buf)
+}
+
+func (s *hasher) combine(seed uint64, parts ...uint64) uint64 {
+	buf := binary.LittleEndian.AppendUint64(s.buf[:0], seed)
+
+	for _, p := range parts {
+		buf = binary.LittleEndian.AppendUint64(buf, p)
+	}
+
+	return s.sum(buf)
+}
+
+func (s *hasher) value(v *JsonValue, opts *EqualOptions) uint64 {
+	switch x := v.Value().(type) {
+	case *JsonFalse:
+		return s.bytes(hashFalse)
+	case *JsonNull:
+		return s.bytes(hashNull)
+	case *JsonTrue:
+		return s.bytes(hashTrue)
+	case *JsonNumber:
+		return s.bytes(hashNumber, numberKey(x.Text, opts))
+	case *JsonString:
+		return s.bytes(hashString, x.Text)
+	case *JsonObject:
+		h := s.bytes(hashObject)
+
+		if opts.OrderedMembers {
+			for _, m := range x.Members {
+				h = s.combine(h, s.bytes(hashString, m.Key), s.value(m.Value, opts))
+			}
+
+			return h
+		}
+
+		// Member hashes are summed so that the result does not depend on order.
+		var sum uint64
+		idx := x.lastIndexes()
+
+		for i, m := range x.Members {
+			if idx[m.Key] == i {
+				sum += s.combine(s.bytes(hashString, m.Key), s.value(m.Value, opts))
+			}
+		}
+
+		return s.combine(h, sum)
+	case *JsonArray:
+		h := s.bytes(hashArray)
+
+		for _, e := range x.Elements {
+			h = s.combine(h, s.value(e, opts))
+		}
+
+		return h
+	case *JsonInvalid:
+		return s.bytes(hashInvalid)
+	default:
+		return s.bytes(hashEmpty)
+	}
+}
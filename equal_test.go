@@ -0,0 +1,86 @@
+package jsonast_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestEqual(t *testing.T) {
+	ordered := &jsonast.EqualOptions{OrderedMembers: true}
+	numeric := &jsonast.EqualOptions{Numbers: jsonast.NumberCompareNumeric}
+
+	tests := []struct {
+		a        string
+		b        string
+		opts     *jsonast.EqualOptions
+		expected bool
+	}{
+		{`null`, `null`, nil, true},
+		{`true`, `false`, nil, false},
+		{`"ab"`, `"ab"`, nil, true},
+		{`1`, `"1"`, nil, false},
+		{`1.0`, `1`, nil, false},
+		{`1.0`, `1`, numeric, true},
+		{`-0`, `0e10`, numeric, true},
+		{`12e-1`, `1.2`, numeric, true},
+		{`[1,2]`, `[1,2]`, nil, true},
+		{`[1,2]`, `[2,1]`, nil, false},
+		{`[1,2]`, `[1,2,3]`, nil, false},
+		{`{"a":1,"b":[true]}`, `{"b":[true],"a":1}`, nil, true},
+		{`{"a":1,"b":[true]}`, `{"b":[true],"a":1}`, ordered, false},
+		{`{"a":1,"b":[true]}`, `{"a":1,"b":[true]}`, ordered, true},
+		{`{"a":1}`, `{"a":1,"b":2}`, nil, false},
+		{`{"a":1,"b":2}`, `{"a":1}`, nil, false},
+		{`{"a":1,"a":2}`, `{"a":2}`, nil, true},
+		{`{"a":1,"a":2}`, `{"a":2}`, ordered, false},
+		{`{"a":{"b":1.50}}`, `{"a":{"b":15e-1}}`, numeric, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, err := jsonast.ParseBytes("a.json", []byte(tt.a))
+			require.NoError(t, err)
+			b, err := jsonast.ParseBytes("b.json", []byte(tt.b))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, jsonast.Equal(a, b, tt.opts))
+			assert.Equal(t, tt.expected, jsonast.Equal(b, a, tt.opts))
+			assert.Equal(t, tt.expected, jsonast.Hash(a, tt.opts) == jsonast.Hash(b, tt.opts))
+		})
+	}
+}
+
+func TestEqual_IgnoresShape(t *testing.T) {
+	a := &jsonast.JsonValue{String: pstr("s")}
+	b := &jsonast.JsonValue{String: vstr("s")}
+	assert.True(t, jsonast.Equal(a, b, nil))
+	assert.Equal(t, jsonast.Hash(a, nil), jsonast.Hash(b, nil))
+}
+
+func TestHash_Stable(t *testing.T) {
+	v, err := jsonast.ParseBytes("a.json", []byte(`{"a":[1,"x",null,true,false]}`))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0x9fd83401bc5f8e1f), jsonast.Hash(v, nil))
+}
+
+func TestEqual_LargeObject(t *testing.T) {
+	const n = 50000
+	a := jsonast.NewObject()
+	b := jsonast.NewObject()
+
+	for i := range n {
+		a.Object.Members = append(a.Object.Members, jsonast.NewMember(strconv.Itoa(i), jsonast.NewNumberFromInt(int64(i))))
+		b.Object.Members = append(b.Object.Members, jsonast.NewMember(strconv.Itoa(n-1-i), jsonast.NewNumberFromInt(int64(n-1-i))))
+	}
+
+	assert.True(t, jsonast.Equal(a, b, nil))
+	assert.Equal(t, jsonast.Hash(a, nil), jsonast.Hash(b, nil))
+
+	b.Object.Members[0].Value = jsonast.NewNull()
+	assert.False(t, jsonast.Equal(a, b, nil))
+	assert.NotEqual(t, jsonast.Hash(a, nil), jsonast.Hash(b, nil))
+}
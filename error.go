@@ -0,0 +1,153 @@
+package jsonast
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type ErrorCategory int
+
+const (
+	ErrorCategorySyntax ErrorCategory = iota
+	ErrorCategoryLexical
+	ErrorCategoryLimit
+	ErrorCategoryEncoding
+)
+
+func (c ErrorCategory) String() string {
+	switch c {
+	case ErrorCategorySyntax:
+		return "syntax"
+	case ErrorCategoryLexical:
+		return "lexical"
+	case ErrorCategoryLimit:
+		return "limit"
+	case ErrorCategoryEncoding:
+		return "encoding"
+	default:
+		return fmt.Sprintf("ErrorCategory(%d)", int(c))
+	}
+}
+
+type ParseError struct {
+	Category ErrorCategory
+	Pos      lexer.Position
+	Token    string
+	Expected []string
+	Msg      string
+	Err      error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Excerpt renders the line of src containing the error with the offending token underlined.
+func (e *ParseError) Excerpt(src []byte) string {
+	if e.Pos.Offset < 0 || e.Pos.Offset > len(src) {
+		return ""
+	}
+
+	start := bytes.LastIndexByte(src[:e.Pos.Offset], '\n') + 1
+	line := src[start:]
+
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	runes := []rune(string(line))
+	prefix := &strings.Builder{}
+
+	for i, r := range runes {
+		if i >= e.Pos.Column-1 {
+			break
+		} else if r == '\t' {
+			prefix.WriteRune('\t')
+		} else {
+			prefix.WriteRune(' ')
+		}
+	}
+
+	width := max(min(utf8.RuneCountInString(e.Token), len(runes)-e.Pos.Column+1), 1)
+
+	gutter := strconv.Itoa(e.Pos.Line)
+	blank := strings.Repeat(" ", len(gutter))
+
+	return fmt.Sprintf("%s | %s\n%s | %s^%s", gutter, line, blank, prefix, strings.Repeat("~", width-1))
+}
+
+func newParseError(err error) error {
+	var parseErr *ParseError
+	var limitErr *LimitError
+	var unexpectedErr *participle.UnexpectedTokenError
+	var participleErr participle.Error
+
+	if errors.As(err, &parseErr) {
+		return parseErr
+	} else if errors.As(err, &limitErr) {
+		return &ParseError{
+			Category: ErrorCategoryLimit,
+			Pos:      limitErr.Pos,
+			Msg:      fmt.Sprintf("%s exceeded (%d)", limitErr.Limit, limitErr.Max),
+			Err:      limitErr,
+		}
+	} else if errors.As(err, &unexpectedErr) {
+		msg := unexpectedErr.Message()
+		var expected []string
+
+		if i := strings.Index(msg, " (expected "); i >= 0 {
+			exp := strings.TrimSuffix(msg[i+len(" (expected "):], ")")
+
+			if s, err := strconv.Unquote(exp); err == nil {
+				exp = s
+			}
+
+			expected = []string{exp}
+		}
+
+		return &ParseError{
+			Category: ErrorCategorySyntax,
+			Pos:      unexpectedErr.Position(),
+			Token:    unexpectedErr.Unexpected.String(),
+			Expected: expected,
+			Msg:      msg,
+			Err:      err,
+		}
+	} else if errors.As(err, &participleErr) {
+		return &ParseError{
+			Category: ErrorCategorySyntax,
+			Pos:      participleErr.Position(),
+			Msg:      participleErr.Message(),
+			Err:      err,
+		}
+	}
+
+	return err
+}
+
+func isEncodingError(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+
+	for _, bom := range [][]byte{{0xef, 0xbb, 0xbf}, {0xfe, 0xff}, {0xff, 0xfe}} {
+		if bytes.HasPrefix(b, bom) {
+			return true
+		}
+	}
+
+	r, _ := utf8.DecodeRune(b)
+	return r == utf8.RuneError || b[0] == 0
+}
@@ -0,0 +1,221 @@
+package jsonast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected *jsonast.ParseError
+		message  string
+	}{
+		{
+			name: "syntax",
+			json: "{\n  \"a\": [1}",
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategorySyntax,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 11, Line: 2, Column: 10},
+				Token:    "}",
+				Expected: []string{",", "]"},
+				Msg:      "invalid character '}' after array element",
+			},
+			message: "<filename>:2:10: invalid character '}' after array element",
+		},
+		{
+			name: "missing colon",
+			json: `{"a" 1}`,
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategorySyntax,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 5, Line: 1, Column: 6},
+				Token:    "1",
+				Expected: []string{":"},
+				Msg:      "invalid character '1' after object key",
+			},
+			message: "<filename>:1:6: invalid character '1' after object key",
+		},
+		{
+			name: "missing comma between members",
+			json: `{"a":1 "b":2}`,
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategorySyntax,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 7, Line: 1, Column: 8},
+				Token:    `"`,
+				Expected: []string{",", "}"},
+				Msg:      `invalid character '"' after object key:value pair`,
+			},
+			message: `<filename>:1:8: invalid character '"' after object key:value pair`,
+		},
+		{
+			name: "trailing comma",
+			json: `[1,]`,
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategorySyntax,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 2, Line: 1, Column: 3},
+				Token:    ",",
+				Expected: []string{"value"},
+				Msg:      "invalid character ',' looking for beginning of value",
+			},
+			message: "<filename>:1:3: invalid character ',' looking for beginning of value",
+		},
+		{
+			name: "missing value",
+			json: `{"a":}`,
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategorySyntax,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 5, Line: 1, Column: 6},
+				Token:    "}",
+				Expected: []string{"value"},
+				Msg:      "missing value after object key",
+			},
+			message: "<filename>:1:6: missing value after object key",
+		},
+		{
+			name: "non-string key",
+			json: `{1:2}`,
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategorySyntax,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 1, Line: 1, Column: 2},
+				Token:    "1",
+				Expected: []string{"string"},
+				Msg:      "object member name must be a string",
+			},
+			message: "<filename>:1:2: object member name must be a string",
+		},
+		{
+			name: "invalid character",
+			json: `[1 @]`,
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategoryLexical,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 3, Line: 1, Column: 4},
+				Token:    "@",
+				Msg:      "invalid character '@' after array element",
+			},
+			message: "<filename>:1:4: invalid character '@' after array element",
+		},
+		{
+			name: "unexpected eof",
+			json: `{"a":[1]`,
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategorySyntax,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 8, Line: 1, Column: 9},
+				Token:    "<EOF>",
+				Expected: []string{"}"},
+				Msg:      `unexpected token "<EOF>" (expected "}")`,
+			},
+			message: `<filename>:1:9: unexpected token "<EOF>" (expected "}")`,
+		},
+		{
+			name: "trailing token",
+			json: `1 2`,
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategorySyntax,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 2, Line: 1, Column: 3},
+				Token:    "2",
+				Msg:      `unexpected token "2"`,
+			},
+			message: `<filename>:1:3: unexpected token "2"`,
+		},
+		{
+			name: "truncated literal",
+			json: `[tru`,
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategoryLexical,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 4, Line: 1, Column: 5},
+				Token:    "<EOF>",
+				Msg:      "unexpected EOF",
+			},
+			message: "<filename>:1:5: unexpected EOF",
+		},
+		{
+			name: "bom",
+			json: "\ufeff{}",
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategoryEncoding,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 0, Line: 1, Column: 1},
+				Token:    "\ufeff",
+				Msg:      `invalid character '\ufeff' looking for beginning of value`,
+			},
+			message: `<filename>:1:1: invalid character '\ufeff' looking for beginning of value`,
+		},
+		{
+			name: "invalid utf-8",
+			json: "[\xff]",
+			expected: &jsonast.ParseError{
+				Category: jsonast.ErrorCategoryEncoding,
+				Pos:      lexer.Position{Filename: "<filename>", Offset: 1, Line: 1, Column: 2},
+				Token:    `"\xff"`,
+				Msg:      `invalid character '\xff' looking for beginning of value`,
+			},
+			message: `<filename>:1:2: invalid character '\xff' looking for beginning of value`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, parse := range []func() (*jsonast.JsonValue, error){
+				func() (*jsonast.JsonValue, error) { return jsonast.ParseBytes("<filename>", []byte(tt.json)) },
+				func() (*jsonast.JsonValue, error) { return jsonast.Parse("<filename>", strings.NewReader(tt.json)) },
+				func() (*jsonast.JsonValue, error) {
+					return jsonast.ParseBytesWithOptions("<filename>", []byte(tt.json), &jsonast.ParseOptions{})
+				},
+			} {
+				_, err := parse()
+				var parseErr *jsonast.ParseError
+				require.ErrorAs(t, err, &parseErr)
+				assert.EqualError(t, err, tt.message)
+				assert.NotNil(t, parseErr.Unwrap())
+				parseErr.Err = nil
+				assert.Equal(t, tt.expected, parseErr)
+			}
+		})
+	}
+}
+
+func TestParseError_Limit(t *testing.T) {
+	_, err := jsonast.ParseBytesWithOptions("<filename>", []byte(`[[1]]`), &jsonast.ParseOptions{MaxDepth: 1})
+	var parseErr *jsonast.ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, jsonast.ErrorCategoryLimit, parseErr.Category)
+	assert.Equal(t, 2, parseErr.Pos.Column)
+	assert.EqualError(t, err, "<filename>:1:2: max depth exceeded (1)")
+	var limitErr *jsonast.LimitError
+	assert.ErrorAs(t, err, &limitErr)
+}
+
+func TestParseError_Excerpt(t *testing.T) {
+	src := "{\n\t\"foo\": tru,\n  \"bar\": 1\n}"
+	_, err := jsonast.ParseBytes("<filename>", []byte(src))
+	var parseErr *jsonast.ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "2 | \t\"foo\": tru,\n  | \t          ^", parseErr.Excerpt([]byte(src)))
+
+	src = "[1,\n  2\n  3]"
+	_, err = jsonast.ParseBytes("<filename>", []byte(src))
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "3 |   3]\n  |   ^", parseErr.Excerpt([]byte(src)))
+
+	src = `{"key": "value"`
+	_, err = jsonast.ParseBytes("<filename>", []byte(src))
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "1 | {\"key\": \"value\"\n  |                ^", parseErr.Excerpt([]byte(src)))
+
+	src = `[1 2]`
+	parseErr = &jsonast.ParseError{Pos: lexer.Position{Offset: 3, Line: 1, Column: 4}, Token: "200"}
+	assert.Equal(t, "1 | [1 2]\n  |    ^~", parseErr.Excerpt([]byte(src)))
+	assert.Empty(t, parseErr.Excerpt([]byte("")))
+}
+
+func TestErrorCategory_String(t *testing.T) {
+	assert.Equal(t, "syntax", jsonast.ErrorCategorySyntax.String())
+	assert.Equal(t, "lexical", jsonast.ErrorCategoryLexical.String())
+	assert.Equal(t, "limit", jsonast.ErrorCategoryLimit.String())
+	assert.Equal(t, "encoding", jsonast.ErrorCategoryEncoding.String())
+}
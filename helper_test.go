@@ -1,19 +1,16 @@
 package jsonast_test
 
 import (
+	"github.com/alecthomas/participle/v2/lexer"
 	"github.com/winebarrel/jsonast"
 )
 
 func vstr(v string) *jsonast.JsonString {
-	s := &jsonast.JsonString{}
-	s.UnmarshalText([]byte(v)) //nolint:errcheck
-	return s
+	return jsonast.NewString(v).String
 }
 
 func pstr(v string) *jsonast.JsonString {
-	s := vstr(v)
-	jsonast.MakeStrNullable(s)
-	return s
+	return jsonast.NewNullableString(v).String
 }
 
 func vnum(v string) *jsonast.JsonNumber {
@@ -24,36 +21,83 @@ func vnum(v string) *jsonast.JsonNumber {
 
 func pnum(v string) *jsonast.JsonNumber {
 	n := vnum(v)
-	jsonast.MakeNumNullable(n)
+	n.SetNullable(true)
 	return n
 }
 
 func vtrue() *jsonast.JsonTrue {
-	return &jsonast.JsonTrue{}
+	return jsonast.NewBool(true).True
 }
 
 func ptrue() *jsonast.JsonTrue {
-	t := vtrue()
-	jsonast.MakeTrueNullable(t)
-	return t
+	return jsonast.NewNullableBool(true).True
 }
 
 func vfalse() *jsonast.JsonFalse {
-	return &jsonast.JsonFalse{}
+	return jsonast.NewBool(false).False
 }
 
 func pfalse() *jsonast.JsonFalse {
-	f := vfalse()
-	jsonast.MakeFalseNullable(f)
-	return f
+	return jsonast.NewNullableBool(false).False
 }
 
 func vnull() *jsonast.JsonNull {
-	return &jsonast.JsonNull{}
+	return jsonast.NewNull().Null
 }
 
 func anynull() *jsonast.JsonNull {
-	null := &jsonast.JsonNull{}
-	jsonast.MakeNullAny(null)
-	return null
+	return jsonast.NewAny().Null
+}
+
+func stripSource(v *jsonast.JsonValue) *jsonast.JsonValue {
+	v.Pos = lexer.Position{}
+
+	if v.IsString() {
+		v.String.Raw = ""
+	} else if v.IsObject() {
+		for _, m := range v.Object.Members {
+			m.Pos = lexer.Position{}
+			m.RawKey = ""
+			stripSource(m.Value)
+		}
+	} else if v.IsArray() {
+		for _, e := range v.Array.Elements {
+			stripSource(e)
+		}
+	}
+
+	return v
+}
+
+func stripInvalid(v *jsonast.JsonValue) *jsonast.JsonValue {
+	if v.IsInvalid() {
+		v.Invalid.Err = nil
+	} else if v.IsObject() {
+		for _, m := range v.Object.Members {
+			stripInvalid(m.Value)
+		}
+	} else if v.IsArray() {
+		for _, e := range v.Array.Elements {
+			stripInvalid(e)
+		}
+	}
+
+	return v
+}
+
+func stripSyntax(v *jsonast.JsonValue) *jsonast.JsonValue {
+	v.Syntax = nil
+
+	if v.IsObject() {
+		for _, m := range v.Object.Members {
+			m.Syntax = nil
+			stripSyntax(m.Value)
+		}
+	} else if v.IsArray() {
+		for _, e := range v.Array.Elements {
+			stripSyntax(e)
+		}
+	}
+
+	return v
 }
@@ -0,0 +1,167 @@
+package jsonast
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Inferrer folds documents into a single shape with UnionType.
+// Each document is reduced before it is merged, so only the shape is retained.
+// String samples are truncated to MaxSampleLen bytes; the zero value keeps no sample text.
+// An Inferrer is not safe for concurrent use; shard documents across Inferrers and Merge them.
+type Inferrer struct {
+	MaxSampleLen int
+	shape        *JsonValue
+	count        int
+}
+
+func NewInferrer(maxSampleLen int) *Inferrer {
+	return &Inferrer{MaxSampleLen: maxSampleLen}
+}
+
+func (inf *Inferrer) Shape() *JsonValue {
+	return inf.shape
+}
+
+func (inf *Inferrer) Count() int {
+	return inf.count
+}
+
+func (inf *Inferrer) Add(v *JsonValue) {
+	inf.add(inf.reduce(v), 1)
+}
+
+func (inf *Inferrer) AddBytes(filename string, src []byte) error {
+	v, err := ParseBytes(filename, src)
+
+	if err != nil {
+		return err
+	}
+
+	inf.Add(v)
+	return nil
+}
+
+func (inf *Inferrer) AddReader(filename string, r io.Reader) error {
+	v, err := Parse(filename, r)
+
+	if err != nil {
+		return err
+	}
+
+	inf.Add(v)
+	return nil
+}
+
+func (inf *Inferrer) AddNDJSON(filename string, r io.Reader) error {
+	br := bufio.NewReader(r)
+	pos := lexer.Position{Filename: filename, Line: 1, Column: 1}
+
+	for {
+		line, err := br.ReadBytes('\n')
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			v, perr := parseAt(pos, bytes.NewReader(line), nil)
+
+			if perr != nil {
+				return perr
+			}
+
+			inf.Add(v)
+		}
+
+		pos.Offset += len(line)
+		pos.Line++
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func (inf *Inferrer) Merge(other *Inferrer) {
+	if other.shape == nil {
+		return
+	}
+
+	inf.add(inf.reduce(other.shape), other.count)
+}
+
+func (inf *Inferrer) add(v *JsonValue, n int) {
+	if inf.shape == nil {
+		inf.shape = v
+	} else {
+		inf.shape = inf.shape.union(v, true)
+	}
+
+	inf.count += n
+}
+
+func (inf *Inferrer) reduce(v *JsonValue) *JsonValue {
+	switch o := v.Value().(type) {
+	case *JsonObject:
+		members := make([]*JsonObjectMember, len(o.Members))
+
+		for i, m := range o.Members {
+			members[i] = &JsonObjectMember{Key: m.Key, Value: inf.reduce(m.Value)}
+		}
+
+		var omittableKeys map[string]struct{}
+
+		if o.OmittableKeys != nil {
+			omittableKeys = make(map[string]struct{}, len(o.OmittableKeys))
+
+			for k := range o.OmittableKeys {
+				omittableKeys[k] = struct{}{}
+			}
+		}
+
+		return &JsonValue{Object: &JsonObject{Members: members, OmittableKeys: omittableKeys}}
+	case *JsonArray:
+		elems := make([]*JsonValue, len(o.Elements))
+
+		for i, e := range o.Elements {
+			elems[i] = inf.reduce(e)
+		}
+
+		return (&JsonArray{Elements: elems}).union(nil, true)
+	case *JsonString:
+		newval := *o
+		newval.Text = truncateText(o.Text, inf.MaxSampleLen)
+		newval.Raw = ""
+		return &JsonValue{String: &newval}
+	case *JsonNumber:
+		newval := *o
+		return &JsonValue{Number: &newval}
+	case *JsonTrue:
+		newval := *o
+		return &JsonValue{True: &newval}
+	case *JsonFalse:
+		newval := *o
+		return &JsonValue{False: &newval}
+	case *JsonNull:
+		newval := *o
+		return &JsonValue{Null: &newval}
+	default:
+		return v
+	}
+}
+
+func truncateText(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
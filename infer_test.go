@@ -0,0 +1,121 @@
+package jsonast_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestInferrer_AddBytes(t *testing.T) {
+	inf := jsonast.NewInferrer(3)
+	require.NoError(t, inf.AddBytes("a.json", []byte(`{"id":1,"name":"alice","tags":["x","y"]}`)))
+	require.NoError(t, inf.AddBytes("b.json", []byte(`{"id":2,"name":null}`)))
+	require.NoError(t, inf.AddBytes("c.json", []byte(`{"id":3,"name":"bob","tags":[]}`)))
+
+	assert.Equal(t, 3, inf.Count())
+	assert.Equal(t, &jsonast.JsonValue{Object: &jsonast.JsonObject{
+		Members: []*jsonast.JsonObjectMember{
+			{Key: "id", Value: &jsonast.JsonValue{Number: vnum("1")}},
+			{Key: "name", Value: &jsonast.JsonValue{String: pstr("ali")}},
+			{Key: "tags", Value: &jsonast.JsonValue{Array: &jsonast.JsonArray{
+				Elements: []*jsonast.JsonValue{{String: vstr("x")}},
+			}}},
+		},
+		OmittableKeys: map[string]struct{}{"tags": {}},
+	}}, inf.Shape())
+}
+
+func TestInferrer_ZeroValue(t *testing.T) {
+	var inf jsonast.Inferrer
+	assert.Nil(t, inf.Shape())
+	require.NoError(t, inf.AddReader("<filename>", strings.NewReader(`["héllo","world",null]`)))
+
+	assert.Equal(t, &jsonast.JsonValue{Array: &jsonast.JsonArray{
+		Elements: []*jsonast.JsonValue{{String: pstr("")}},
+	}}, inf.Shape())
+}
+
+func TestInferrer_AddNDJSON(t *testing.T) {
+	inf := jsonast.NewInferrer(0)
+	ndjson := "{\"a\":1}\n\n{\"b\":true}\r\n{\"a\":2,\"b\":false}"
+	require.NoError(t, inf.AddNDJSON("<filename>", strings.NewReader(ndjson)))
+
+	assert.Equal(t, 3, inf.Count())
+	assert.Equal(t, &jsonast.JsonValue{Object: &jsonast.JsonObject{
+		Members: []*jsonast.JsonObjectMember{
+			{Key: "a", Value: &jsonast.JsonValue{Number: vnum("1")}},
+			{Key: "b", Value: &jsonast.JsonValue{True: vtrue()}},
+		},
+		OmittableKeys: map[string]struct{}{"a": {}, "b": {}},
+	}}, inf.Shape())
+}
+
+func TestInferrer_AddNDJSON_Err(t *testing.T) {
+	inf := jsonast.NewInferrer(0)
+	err := inf.AddNDJSON("<filename>", strings.NewReader("{\"a\":1}\n{\"a\":}\n"))
+	assert.ErrorContains(t, err, `<filename>:2:`)
+	assert.Equal(t, 1, inf.Count())
+}
+
+func TestInferrer_OmittableKeysAccumulate(t *testing.T) {
+	inf := jsonast.NewInferrer(0)
+	inf.Add(&jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+		{Key: "a", Value: &jsonast.JsonValue{Number: vnum("1")}},
+	}}})
+	inf.Add(&jsonast.JsonValue{Object: &jsonast.JsonObject{}})
+	inf.Add(&jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+		{Key: "a", Value: &jsonast.JsonValue{Number: vnum("2")}},
+	}}})
+
+	assert.Equal(t, map[string]struct{}{"a": {}}, inf.Shape().Object.OmittableKeys)
+}
+
+func TestInferrer_Merge(t *testing.T) {
+	docs := []string{
+		`{"id":1,"v":"a"}`,
+		`{"id":2,"v":null}`,
+		`{"id":3}`,
+		`{"id":4,"v":"d","extra":[1,2]}`,
+	}
+
+	shards := make([]*jsonast.Inferrer, 2)
+	errs := make([]error, len(shards))
+	var wg sync.WaitGroup
+
+	for i := range shards {
+		shards[i] = jsonast.NewInferrer(1)
+
+		wg.Go(func() {
+			for _, d := range docs[i*2 : i*2+2] {
+				if err := shards[i].AddBytes("<filename>", []byte(d)); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+		})
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	merged := jsonast.NewInferrer(1)
+	merged.Merge(shards[0])
+	merged.Merge(shards[1])
+	merged.Merge(jsonast.NewInferrer(1))
+
+	seq := jsonast.NewInferrer(1)
+
+	for _, d := range docs {
+		require.NoError(t, seq.AddBytes("<filename>", []byte(d)))
+	}
+
+	assert.Equal(t, 4, merged.Count())
+	assert.Equal(t, seq.Shape(), merged.Shape())
+}
@@ -0,0 +1,55 @@
+package jsonast
+
+import "iter"
+
+// All yields every member in order, including duplicate keys.
+func (v *JsonObject) All() iter.Seq2[string, *JsonValue] {
+	return func(yield func(string, *JsonValue) bool) {
+		for _, m := range v.Members {
+			if !yield(m.Key, m.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (v *JsonArray) All() iter.Seq2[int, *JsonValue] {
+	return func(yield func(int, *JsonValue) bool) {
+		for i, e := range v.Elements {
+			if !yield(i, e) {
+				return
+			}
+		}
+	}
+}
+
+// Descendants yields every node below v with its JSON Pointer, in document order.
+// v itself is not yielded.
+func (v *JsonValue) Descendants() iter.Seq2[string, *JsonValue] {
+	return func(yield func(string, *JsonValue) bool) {
+		v.walk("", yield)
+	}
+}
+
+func (v *JsonValue) walk(path string, yield func(string, *JsonValue) bool) bool {
+	switch {
+	case v.Object != nil:
+		for _, m := range v.Object.Members {
+			p := appendPointer(path, m.Key)
+
+			if !yield(p, m.Value) || !m.Value.walk(p, yield) {
+				return false
+			}
+		}
+	case v.Array != nil:
+		for i, e := range v.Array.Elements {
+			p := appendIndexPointer(path, i)
+
+			if !yield(p, e) || !e.walk(p, yield) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
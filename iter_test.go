@@ -0,0 +1,79 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestObject_All(t *testing.T) {
+	v, err := jsonast.ParseBytes("", []byte(`{"a":1,"b":2,"a":3}`))
+	require.NoError(t, err)
+
+	keys := []string{}
+	texts := []string{}
+
+	for k, e := range v.Object.All() {
+		keys = append(keys, k)
+		texts = append(texts, e.Number.Text)
+	}
+
+	assert.Equal(t, []string{"a", "b", "a"}, keys)
+	assert.Equal(t, []string{"1", "2", "3"}, texts)
+
+	for k := range v.Object.All() {
+		assert.Equal(t, "a", k)
+		break
+	}
+}
+
+func TestArray_All(t *testing.T) {
+	v, err := jsonast.ParseBytes("", []byte(`["x","y","z"]`))
+	require.NoError(t, err)
+
+	texts := map[int]string{}
+
+	for i, e := range v.Array.All() {
+		if i == 2 {
+			break
+		}
+
+		texts[i] = e.String.Text
+	}
+
+	assert.Equal(t, map[int]string{0: "x", 1: "y"}, texts)
+}
+
+func TestValue_Descendants(t *testing.T) {
+	v, err := jsonast.ParseBytes("", []byte(`{"a":[1,{"b/c":null}],"d":{"e~":true},"f":"s"}`))
+	require.NoError(t, err)
+
+	paths := []string{}
+
+	for p, n := range v.Descendants() {
+		lookup, err := v.Lookup(p)
+		require.NoError(t, err)
+		assert.Same(t, lookup, n)
+		paths = append(paths, p)
+	}
+
+	assert.Equal(t, []string{"/a", "/a/0", "/a/1", "/a/1/b~1c", "/d", "/d/e~0", "/f"}, paths)
+
+	paths = []string{}
+
+	for p := range v.Descendants() {
+		if p == "/a/1/b~1c" {
+			break
+		}
+
+		paths = append(paths, p)
+	}
+
+	assert.Equal(t, []string{"/a", "/a/0", "/a/1"}, paths)
+
+	for range jsonast.NewString("s").Descendants() {
+		t.Fatal("scalars have no descendants")
+	}
+}
@@ -3,8 +3,11 @@ package jsonast
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/alecthomas/participle/v2/lexer"
 )
@@ -38,27 +41,45 @@ func (l *JsonDefinition) Symbols() map[string]lexer.TokenType {
 }
 
 func (l *JsonDefinition) Lex(filename string, r io.Reader) (lexer.Lexer, error) {
+	pos := lexer.Position{
+		Filename: filename,
+		Line:     1,
+		Column:   1,
+	}
+
+	return newJsonLexer(pos, r, nil), nil
+}
+
+func newJsonLexer(pos lexer.Position, r io.Reader, opts *ParseOptions) *JsonLexer {
+	if opts != nil && opts.MaxInputBytes > 0 {
+		r = &limitedReader{r: r, n: int64(opts.MaxInputBytes)}
+	}
+
 	buf := &bytes.Buffer{}
 	decoder := json.NewDecoder(io.TeeReader(r, buf))
 	decoder.UseNumber()
 
-	lex := &JsonLexer{
+	return &JsonLexer{
 		decoder: decoder,
 		buf:     buf,
-		pos: lexer.Position{
-			Filename: filename,
-			Line:     1,
-			Column:   1,
-		},
+		pos:     pos,
+		opts:    opts,
 	}
-
-	return lex, nil
 }
 
 type JsonLexer struct {
 	decoder *json.Decoder
 	buf     *bytes.Buffer
 	pos     lexer.Position
+	opts    *ParseOptions
+	stack   []lexFrame
+	raws    []string
+}
+
+type lexFrame struct {
+	object bool
+	count  int
+	key    bool
 }
 
 func (l *JsonLexer) Next() (lexer.Token, error) {
@@ -71,14 +92,20 @@ func (l *JsonLexer) Next() (lexer.Token, error) {
 		return tok, err
 	}
 
+	trivia := len(span) - len(bytes.TrimLeft(span, " \t\r\n,:"))
+	l.pos.Advance(string(span[:trivia]))
 	tok.Pos = l.pos
-	l.pos.Advance(string(span))
+	l.pos.Advance(string(span[trivia:]))
 
 	if err == io.EOF {
 		tok.Type = lexer.EOF
 		return tok, nil
+	} else if errors.Is(err, errInputLimit) {
+		pos := l.pos
+		pos.Advance(l.buf.String())
+		return tok, &LimitError{Limit: LimitInputBytes, Max: l.opts.MaxInputBytes, Pos: pos}
 	} else if err != nil {
-		return tok, fmt.Errorf("%s: %w", tok.Pos, err)
+		return tok, l.lexError(startOffset, err)
 	}
 
 	switch v := rawTok.(type) {
@@ -102,7 +129,169 @@ func (l *JsonLexer) Next() (lexer.Token, error) {
 	case string:
 		tok.Type = TokenTypeString
 		tok.Value = v
+		l.raws = append(l.raws, string(span[trivia:]))
+	}
+
+	if l.opts != nil {
+		if err := l.checkLimits(tok); err != nil {
+			return tok, err
+		}
 	}
 
 	return tok, nil
 }
+
+func (l *JsonLexer) lexError(offset int64, err error) error {
+	var syntaxErr *json.SyntaxError
+	rest := l.buf.Bytes()
+	pos := l.pos
+
+	if errors.As(err, &syntaxErr) {
+		n := min(max(int(syntaxErr.Offset-offset), 0), len(rest))
+		_, size := utf8.DecodeLastRune(rest[:n])
+		n -= size
+		pos.Advance(string(rest[:n]))
+		rest = rest[n:]
+	} else if errors.Is(err, io.ErrUnexpectedEOF) {
+		pos.Advance(string(rest))
+		rest = nil
+	} else {
+		return fmt.Errorf("%s: %w", pos, err)
+	}
+
+	category := ErrorCategoryLexical
+	token := lexer.EOFToken(pos).String()
+	var expected []string
+
+	if isEncodingError(rest) {
+		category = ErrorCategoryEncoding
+	} else if syntaxErr != nil && len(rest) > 0 && isTokenStart(rest[0]) {
+		// The decoder also enforces the grammar; a well-formed token in the wrong place is a syntax error.
+		for _, e := range decoderExpectations {
+			if strings.HasSuffix(syntaxErr.Error(), e.suffix) {
+				category = ErrorCategorySyntax
+				expected = e.expected
+				break
+			}
+		}
+	}
+
+	if len(rest) > 0 {
+		r, size := utf8.DecodeRune(rest)
+		token = string(rest[:size])
+
+		if r == utf8.RuneError {
+			token = fmt.Sprintf("%q", rest[:size])
+		}
+	}
+
+	return &ParseError{
+		Category: category,
+		Pos:      pos,
+		Token:    token,
+		Expected: expected,
+		Msg:      err.Error(),
+		Err:      err,
+	}
+}
+
+// decoderExpectations maps the endings of encoding/json syntax error messages to the tokens that were expected.
+var decoderExpectations = []struct {
+	suffix   string
+	expected []string
+}{
+	{suffix: "' after object key", expected: []string{":"}},
+	{suffix: "' after object key:value pair", expected: []string{",", "}"}},
+	{suffix: "' after array element", expected: []string{",", "]"}},
+	{suffix: "' looking for beginning of object key string", expected: []string{"string"}},
+	{suffix: "' looking for beginning of value", expected: []string{"value"}},
+	{suffix: "missing value after object key", expected: []string{"value"}},
+	{suffix: "object member name must be a string", expected: []string{"string"}},
+}
+
+func isTokenStart(c byte) bool {
+	return strings.IndexByte(`{}[],:"-tfn`, c) >= 0 || isDigit(c)
+}
+
+func (l *JsonLexer) checkLimits(tok lexer.Token) error {
+	limitErr := func(limit Limit, max int) error {
+		return &LimitError{Limit: limit, Max: max, Pos: tok.Pos}
+	}
+
+	exceeds := func(n, max int) bool {
+		return max > 0 && n > max
+	}
+
+	if tok.Type == TokenTypeDelim && (tok.Value == "}" || tok.Value == "]") {
+		l.stack = l.stack[:len(l.stack)-1]
+		return nil
+	}
+
+	if n := len(l.stack); n > 0 {
+		top := &l.stack[n-1]
+
+		if top.object && top.key {
+			top.key = false
+			top.count++
+
+			if exceeds(top.count, l.opts.MaxMembers) {
+				return limitErr(LimitMembers, l.opts.MaxMembers)
+			} else if exceeds(len(tok.Value), l.opts.MaxStringLength) {
+				return limitErr(LimitStringLength, l.opts.MaxStringLength)
+			}
+
+			return nil
+		} else if top.object {
+			top.key = true
+		} else {
+			top.count++
+
+			if exceeds(top.count, l.opts.MaxElements) {
+				return limitErr(LimitElements, l.opts.MaxElements)
+			}
+		}
+	}
+
+	switch tok.Type {
+	case TokenTypeDelim:
+		l.stack = append(l.stack, lexFrame{object: tok.Value == "{", key: tok.Value == "{"})
+
+		if exceeds(len(l.stack), l.opts.MaxDepth) {
+			return limitErr(LimitDepth, l.opts.MaxDepth)
+		}
+	case TokenTypeString:
+		if exceeds(len(tok.Value), l.opts.MaxStringLength) {
+			return limitErr(LimitStringLength, l.opts.MaxStringLength)
+		}
+	case TokenTypeNumber:
+		if exceeds(len(tok.Value), l.opts.MaxNumberLength) {
+			return limitErr(LimitNumberLength, l.opts.MaxNumberLength)
+		}
+	}
+
+	return nil
+}
+
+var errInputLimit = errors.New("input limit exceeded")
+
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > r.n+1 {
+		p = p[:r.n+1]
+	}
+
+	n, err := r.r.Read(p)
+
+	if int64(n) > r.n {
+		n = int(r.n)
+		r.n = 0
+		return n, errInputLimit
+	}
+
+	r.n -= int64(n)
+	return n, err
+}
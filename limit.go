@@ -0,0 +1,47 @@
+package jsonast
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type Limit int
+
+const (
+	LimitDepth Limit = iota
+	LimitInputBytes
+	LimitStringLength
+	LimitNumberLength
+	LimitMembers
+	LimitElements
+)
+
+func (l Limit) String() string {
+	switch l {
+	case LimitDepth:
+		return "max depth"
+	case LimitInputBytes:
+		return "max input bytes"
+	case LimitStringLength:
+		return "max string length"
+	case LimitNumberLength:
+		return "max number length"
+	case LimitMembers:
+		return "max members"
+	case LimitElements:
+		return "max elements"
+	default:
+		return fmt.Sprintf("Limit(%d)", int(l))
+	}
+}
+
+type LimitError struct {
+	Limit Limit
+	Max   int
+	Pos   lexer.Position
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %s exceeded (%d)", e.Pos, e.Limit, e.Max)
+}
@@ -0,0 +1,107 @@
+package jsonast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestParseWithOptions_Limits(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		opts     jsonast.ParseOptions
+		expected *jsonast.LimitError
+	}{
+		{
+			name:     "depth",
+			json:     `{"a":[[1]]}`,
+			opts:     jsonast.ParseOptions{MaxDepth: 2},
+			expected: &jsonast.LimitError{Limit: jsonast.LimitDepth, Max: 2, Pos: lexer.Position{Filename: "<filename>", Offset: 6, Line: 1, Column: 7}},
+		},
+		{
+			name:     "input bytes",
+			json:     "[1,\n2,3]",
+			opts:     jsonast.ParseOptions{MaxInputBytes: 6},
+			expected: &jsonast.LimitError{Limit: jsonast.LimitInputBytes, Max: 6, Pos: lexer.Position{Filename: "<filename>", Offset: 6, Line: 2, Column: 3}},
+		},
+		{
+			name:     "string length",
+			json:     `["abc","abcd"]`,
+			opts:     jsonast.ParseOptions{MaxStringLength: 3},
+			expected: &jsonast.LimitError{Limit: jsonast.LimitStringLength, Max: 3, Pos: lexer.Position{Filename: "<filename>", Offset: 7, Line: 1, Column: 8}},
+		},
+		{
+			name:     "key length",
+			json:     `{"abcd":1}`,
+			opts:     jsonast.ParseOptions{MaxStringLength: 3},
+			expected: &jsonast.LimitError{Limit: jsonast.LimitStringLength, Max: 3, Pos: lexer.Position{Filename: "<filename>", Offset: 1, Line: 1, Column: 2}},
+		},
+		{
+			name:     "number length",
+			json:     `[123,1234]`,
+			opts:     jsonast.ParseOptions{MaxNumberLength: 3},
+			expected: &jsonast.LimitError{Limit: jsonast.LimitNumberLength, Max: 3, Pos: lexer.Position{Filename: "<filename>", Offset: 5, Line: 1, Column: 6}},
+		},
+		{
+			name:     "members",
+			json:     `{"a":{"b":1,"c":2},"d":3,"e":4}`,
+			opts:     jsonast.ParseOptions{MaxMembers: 2},
+			expected: &jsonast.LimitError{Limit: jsonast.LimitMembers, Max: 2, Pos: lexer.Position{Filename: "<filename>", Offset: 25, Line: 1, Column: 26}},
+		},
+		{
+			name:     "elements",
+			json:     `[[1,2],{"a":[3]},4]`,
+			opts:     jsonast.ParseOptions{MaxElements: 2},
+			expected: &jsonast.LimitError{Limit: jsonast.LimitElements, Max: 2, Pos: lexer.Position{Filename: "<filename>", Offset: 17, Line: 1, Column: 18}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jsonast.ParseBytesWithOptions("<filename>", []byte(tt.json), &tt.opts)
+			var limitErr *jsonast.LimitError
+			require.ErrorAs(t, err, &limitErr)
+			assert.Equal(t, tt.expected, limitErr)
+
+			_, err = jsonast.ParseWithOptions("<filename>", strings.NewReader(tt.json), &tt.opts)
+			require.ErrorAs(t, err, &limitErr)
+			assert.Equal(t, tt.expected, limitErr)
+		})
+	}
+}
+
+func TestParseWithOptions_WithinLimits(t *testing.T) {
+	json := `{"a":[[1,"xy"]],"b":{"c":12}}`
+	opts := &jsonast.ParseOptions{
+		MaxDepth:        3,
+		MaxInputBytes:   len(json),
+		MaxStringLength: 2,
+		MaxNumberLength: 2,
+		MaxMembers:      2,
+		MaxElements:     2,
+	}
+
+	v, err := jsonast.ParseBytesWithOptions("<filename>", []byte(json), opts)
+	require.NoError(t, err)
+	expected, err := jsonast.ParseBytes("<filename>", []byte(json))
+	require.NoError(t, err)
+	assert.Equal(t, expected, v)
+}
+
+func TestLimitError(t *testing.T) {
+	err := &jsonast.LimitError{Limit: jsonast.LimitDepth, Max: 2, Pos: lexer.Position{Filename: "<filename>", Line: 1, Column: 7}}
+	assert.EqualError(t, err, "<filename>:1:7: max depth exceeded (2)")
+}
+
+func TestParseWithOptions_DeepNesting(t *testing.T) {
+	json := strings.Repeat("[", 100000) + strings.Repeat("]", 100000)
+	_, err := jsonast.ParseWithOptions("<filename>", strings.NewReader(json), &jsonast.ParseOptions{MaxDepth: 64})
+	var limitErr *jsonast.LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, 65, limitErr.Pos.Column)
+}
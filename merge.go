@@ -0,0 +1,104 @@
+package jsonast
+
+import (
+	"slices"
+)
+
+// ApplyMergePatch applies an RFC 7396 JSON Merge Patch to v.
+// Existing members keep their order and new members are appended.
+func (v *JsonValue) ApplyMergePatch(patch *JsonValue) {
+	if !patch.IsObject() {
+		nv := patch.Clone()
+		clearSyntax(nv)
+
+		if v.Syntax != nil {
+			nv.Syntax = &ValueSyntax{Leading: v.Syntax.Leading, Trailing: v.Syntax.Trailing}
+		}
+
+		*v = *nv
+		return
+	}
+
+	if !v.IsObject() {
+		*v = JsonValue{Pos: patch.Pos, Object: &JsonObject{}}
+	}
+
+	obj := v.Object
+	indexed := obj.index != nil
+	obj.index = nil
+
+	for i, pm := range patch.Object.Members {
+		if j, _ := patch.Object.lastMember(pm.Key); j != i {
+			continue
+		}
+
+		_, m := obj.lastMember(pm.Key)
+
+		switch {
+		case pm.Value.IsNull():
+			for k := len(obj.Members) - 1; k >= 0; k-- {
+				if obj.Members[k].Key == pm.Key {
+					obj.Members = slices.Delete(obj.Members, k, k+1)
+					deleteComma(v.Syntax, k)
+				}
+			}
+		case m != nil:
+			m.Value.ApplyMergePatch(pm.Value)
+		default:
+			nv := &JsonValue{}
+			nv.ApplyMergePatch(pm.Value)
+			obj.Members = append(obj.Members, &JsonObjectMember{Pos: pm.Pos, Key: pm.Key, RawKey: pm.RawKey, Value: nv})
+		}
+	}
+
+	if indexed {
+		obj.BuildIndex()
+	}
+}
+
+// GenerateMergePatch returns an RFC 7396 JSON Merge Patch that turns a into b.
+// Merge patches cannot set a member to null, so null members of b are removed instead.
+func GenerateMergePatch(a, b *JsonValue) *JsonValue {
+	if !a.IsObject() || !b.IsObject() {
+		patch := b.Clone()
+		clearSyntax(patch)
+		return patch
+	}
+
+	patch := &JsonObject{Members: []*JsonObjectMember{}}
+
+	ai, bi := a.Object.lastIndexes(), b.Object.lastIndexes()
+
+	for i, m := range a.Object.Members {
+		if ai[m.Key] != i {
+			continue
+		}
+
+		var bm *JsonObjectMember
+
+		if j, ok := bi[m.Key]; ok {
+			bm = b.Object.Members[j]
+		}
+
+		switch {
+		case bm == nil || bm.Value.IsNull():
+			if !m.Value.IsNull() || bm == nil {
+				patch.Members = append(patch.Members, &JsonObjectMember{Key: m.Key, Value: &JsonValue{Null: &JsonNull{}}})
+			}
+		case !Equal(m.Value, bm.Value, nil):
+			patch.Members = append(patch.Members, &JsonObjectMember{Key: m.Key, Value: GenerateMergePatch(m.Value, bm.Value)})
+		}
+	}
+
+	for i, m := range b.Object.Members {
+		if bi[m.Key] != i || m.Value.IsNull() {
+			continue
+		}
+
+		if _, ok := ai[m.Key]; !ok {
+			patch.Members = append(patch.Members, &JsonObjectMember{Key: m.Key, Value: GenerateMergePatch(&JsonValue{}, m.Value)})
+		}
+	}
+
+	return &JsonValue{Object: patch}
+}
@@ -0,0 +1,126 @@
+package jsonast_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct {
+		target   string
+		patch    string
+		expected string
+	}{
+		// RFC 7396 Appendix A
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		// order and duplicates
+		{`{"z":1,"y":{"x":1},"w":2}`, `{"a":0,"y":{"v":1},"z":3}`, `{"z":3,"y":{"x":1,"v":1},"w":2,"a":0}`},
+		{`{"a":1,"b":2,"a":3}`, `{"a":null}`, `{"b":2}`},
+		{`{"a":1}`, `{"a":2,"a":{"b":1}}`, `{"a":{"b":1}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.patch, func(t *testing.T) {
+			target, err := jsonast.ParseBytes("target.json", []byte(tt.target))
+			require.NoError(t, err)
+			patch, err := jsonast.ParseBytes("patch.json", []byte(tt.patch))
+			require.NoError(t, err)
+
+			target.ApplyMergePatch(patch)
+			b, err := target.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+		})
+	}
+}
+
+func TestApplyMergePatch_BuildIndex(t *testing.T) {
+	v, err := jsonast.ParseBytes("a.json", []byte(`{"a":1,"b":2,"o":{"x":1}}`))
+	require.NoError(t, err)
+	v.Object.BuildIndex()
+	o, _ := v.Object.Get("o")
+	o.Object.BuildIndex()
+
+	patch, err := jsonast.ParseBytes("patch.json", []byte(`{"a":null,"c":3,"o":{"x":null,"y":2}}`))
+	require.NoError(t, err)
+	v.ApplyMergePatch(patch)
+	assert.Equal(t, `{"b":2,"o":{"y":2},"c":3}`, fmt.Sprint(v))
+
+	c, ok := v.Object.Get("c")
+	require.True(t, ok)
+	assert.Equal(t, "3", c.Number.Text)
+	assert.False(t, v.Object.Has("a"))
+	b, err := v.Lookup("/b")
+	require.NoError(t, err)
+	assert.Equal(t, "2", b.Number.Text)
+	y, err := v.Lookup("/o/y")
+	require.NoError(t, err)
+	assert.Equal(t, "2", y.Number.Text)
+	assert.False(t, o.Object.Has("x"))
+}
+
+func TestApplyMergePatch_Lossless(t *testing.T) {
+	target, err := jsonast.ParseLossless("target.json", []byte("{\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3\n}\n"))
+	require.NoError(t, err)
+	patch, err := jsonast.ParseBytes("patch.json", []byte(`{"b":null,"c":"x","d":true}`))
+	require.NoError(t, err)
+
+	target.ApplyMergePatch(patch)
+	b, err := target.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": 1,\n  \"c\": \"x\",\"d\":true\n}\n", string(b))
+}
+
+func TestGenerateMergePatch(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected string
+	}{
+		{`{"a":1}`, `{"a":1}`, `{}`},
+		{`{"a":"b","c":{"d":"e","f":"g"}}`, `{"a":"z","c":{"d":"e"},"h":[1]}`, `{"a":"z","c":{"f":null},"h":[1]}`},
+		{`{"a":1,"b":null}`, `{}`, `{"a":null,"b":null}`},
+		{`{"a":1}`, `{"a":null}`, `{"a":null}`},
+		{`{"a":[1,2]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`[1]`, `{"a":1}`, `{"a":1}`},
+		{`{"a":1}`, `"s"`, `"s"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, err := jsonast.ParseBytes("a.json", []byte(tt.a))
+			require.NoError(t, err)
+			b, err := jsonast.ParseBytes("b.json", []byte(tt.b))
+			require.NoError(t, err)
+
+			patch := jsonast.GenerateMergePatch(a, b)
+			out, err := patch.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+
+			// null members of b cannot be expressed, so compare against b with them removed
+			expected := &jsonast.JsonValue{}
+			expected.ApplyMergePatch(b)
+			a.ApplyMergePatch(patch)
+			assert.Empty(t, jsonast.Diff(a, expected))
+		})
+	}
+}
@@ -1,11 +1,17 @@
 package jsonast
 
+import "errors"
+
 type nullable bool
 
 func (v nullable) Nullable() bool {
 	return bool(v)
 }
 
+func (v *nullable) SetNullable(b bool) {
+	*v = nullable(b)
+}
+
 func (v nullable) Or(other bool) nullable {
 	return nullable(bool(v) || other)
 }
@@ -14,6 +20,55 @@ func (v *JsonValue) Nullable() bool {
 	return v.Value().Nullable()
 }
 
+// IsAny reports whether v is the "any" shape produced when incompatible types are unioned.
+func (v *JsonValue) IsAny() bool {
+	return v.Null != nil && v.Null.any
+}
+
+func (v *JsonNull) IsAny() bool {
+	return v.any
+}
+
+func (v *JsonNull) SetAny(b bool) {
+	v.any = b
+}
+
+// SetNullable marks a scalar shape as nullable or not.
+// Only strings, numbers and booleans carry a nullable flag; every other kind reports
+// Nullable() == false, so SetNullable(false) is a no-op for them and SetNullable(true) fails.
+func (v *JsonValue) SetNullable(b bool) error {
+	switch o := v.Value().(type) {
+	case *JsonFalse:
+		o.SetNullable(b)
+	case *JsonTrue:
+		o.SetNullable(b)
+	case *JsonNumber:
+		o.SetNullable(b)
+	case *JsonString:
+		o.SetNullable(b)
+	case *JsonNull:
+		if b {
+			return errors.New("null cannot be nullable")
+		}
+	case *JsonObject:
+		if b {
+			return errors.New("object cannot be nullable")
+		}
+	case *JsonArray:
+		if b {
+			return errors.New("array cannot be nullable")
+		}
+	case *JsonInvalid:
+		if b {
+			return errors.New("invalid value cannot be nullable")
+		}
+	default:
+		return errors.New("empty value cannot be nullable")
+	}
+
+	return nil
+}
+
 type notnullable struct{}
 
 func (v notnullable) Nullable() bool {
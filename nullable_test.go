@@ -0,0 +1,62 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestSetNullable(t *testing.T) {
+	n, err := jsonast.NewNumberFromText("1")
+	require.NoError(t, err)
+
+	for _, v := range []*jsonast.JsonValue{
+		jsonast.NewString("s"),
+		n,
+		jsonast.NewBool(true),
+		jsonast.NewBool(false),
+	} {
+		assert.False(t, v.Nullable())
+		require.NoError(t, v.SetNullable(true))
+		assert.True(t, v.Nullable())
+		assert.True(t, v.Value().Nullable())
+		require.NoError(t, v.SetNullable(false))
+		assert.False(t, v.Nullable())
+	}
+
+	assert.NoError(t, jsonast.NewNull().SetNullable(false))
+	assert.EqualError(t, jsonast.NewNull().SetNullable(true), "null cannot be nullable")
+
+	// the setter agrees with the getter for every kind
+	for _, v := range []*jsonast.JsonValue{jsonast.NewNull(), jsonast.NewAny(), jsonast.NewObject(), jsonast.NewArray()} {
+		assert.False(t, v.Nullable())
+		assert.NoError(t, v.SetNullable(v.Nullable()))
+	}
+	assert.NoError(t, jsonast.NewObject().SetNullable(false))
+	assert.EqualError(t, jsonast.NewObject().SetNullable(true), "object cannot be nullable")
+	assert.EqualError(t, jsonast.NewArray().SetNullable(true), "array cannot be nullable")
+	assert.EqualError(t, (&jsonast.JsonValue{Invalid: &jsonast.JsonInvalid{}}).SetNullable(true), "invalid value cannot be nullable")
+	assert.EqualError(t, (&jsonast.JsonValue{}).SetNullable(true), "empty value cannot be nullable")
+}
+
+func TestIsAny(t *testing.T) {
+	assert.True(t, jsonast.NewAny().IsAny())
+	assert.True(t, jsonast.NewAny().Null.IsAny())
+	assert.False(t, jsonast.NewNull().IsAny())
+	assert.False(t, jsonast.NewString("s").IsAny())
+	assert.False(t, jsonast.NewObject().IsAny())
+
+	v := jsonast.NewNull()
+	v.Null.SetAny(true)
+	assert.True(t, v.IsAny())
+	assert.Equal(t, "any", v.TypeString())
+
+	// shapes built through the public API union like inferred ones
+	s := jsonast.NewString("s")
+	require.NoError(t, s.SetNullable(true))
+	assert.Equal(t, "string|null", jsonast.NewNull().UnionType(jsonast.NewString("s")).TypeString())
+	assert.Equal(t, s.TypeString(), jsonast.NewString("s").UnionType(jsonast.NewNull()).TypeString())
+	assert.True(t, jsonast.NewString("s").UnionType(jsonast.NewBool(true)).IsAny())
+}
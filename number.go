@@ -0,0 +1,226 @@
+package jsonast
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// normalizeNumber rewrites a JSON number as [-]digitsEexp with no leading or trailing zeros in digits,
+// so that numerically equal texts such as "1.50", "15e-1" and "1.5" normalize identically.
+// Text that is not a valid JSON number is returned unchanged.
+func normalizeNumber(text string) string {
+	if !isJSONNumber(text) {
+		return text
+	}
+
+	sign := ""
+	s := text
+
+	if strings.HasPrefix(s, "-") {
+		sign = "-"
+		s = s[1:]
+	}
+
+	exp := new(big.Int)
+
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		if _, ok := exp.SetString(strings.TrimPrefix(s[i+1:], "+"), 10); !ok {
+			return text
+		}
+
+		s = s[:i]
+	}
+
+	intPart, frac, _ := strings.Cut(s, ".")
+	digits := strings.TrimLeft(intPart+frac, "0")
+	exp.Sub(exp, big.NewInt(int64(len(frac))))
+
+	if digits == "" {
+		return "0"
+	}
+
+	trimmed := strings.TrimRight(digits, "0")
+	exp.Add(exp, big.NewInt(int64(len(digits)-len(trimmed))))
+	return sign + trimmed + "e" + exp.String()
+}
+
+// Exponents beyond this are refused by exact conversions instead of allocating huge values.
+const maxExactExponent = 1 << 16
+
+// decompose splits a valid JSON number into sign, significant digits and a decimal exponent.
+func (v *JsonNumber) decompose() (bool, string, int64, error) {
+	if !isJSONNumber(v.Text) {
+		return false, "", 0, fmt.Errorf("%q is not a valid JSON number", v.Text)
+	}
+
+	norm := normalizeNumber(v.Text)
+
+	if norm == "0" {
+		return false, "", 0, nil
+	}
+
+	neg := strings.HasPrefix(norm, "-")
+	digits, e, _ := strings.Cut(strings.TrimPrefix(norm, "-"), "e")
+	exp, err := strconv.ParseInt(e, 10, 64)
+
+	if err != nil {
+		return false, "", 0, fmt.Errorf("exponent of number %s is out of range", v.Text)
+	}
+
+	return neg, digits, exp, nil
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+func (v *JsonNumber) BigInt() (*big.Int, error) {
+	neg, digits, exp, err := v.decompose()
+
+	if err != nil {
+		return nil, err
+	} else if exp < 0 {
+		return nil, fmt.Errorf("number %s is not an integer", v.Text)
+	} else if exp > maxExactExponent {
+		return nil, fmt.Errorf("number %s is too large to convert exactly", v.Text)
+	}
+
+	n, ok := new(big.Int).SetString("0"+digits, 10)
+
+	if !ok {
+		return nil, fmt.Errorf("%q is not a valid JSON number", v.Text)
+	}
+
+	n.Mul(n, pow10(exp))
+
+	if neg {
+		n.Neg(n)
+	}
+
+	return n, nil
+}
+
+func (v *JsonNumber) Int64() (int64, error) {
+	if !isJSONNumber(v.Text) {
+		return 0, fmt.Errorf("%q is not a valid JSON number", v.Text)
+	} else if n, err := strconv.ParseInt(v.Text, 10, 64); err == nil {
+		return n, nil
+	}
+
+	_, digits, exp, err := v.decompose()
+
+	if err != nil {
+		return 0, err
+	} else if exp >= 0 && int64(len(digits))+exp > 19 {
+		return 0, fmt.Errorf("number %s overflows int64", v.Text)
+	}
+
+	n, err := v.BigInt()
+
+	if err != nil {
+		return 0, err
+	} else if !n.IsInt64() {
+		return 0, fmt.Errorf("number %s overflows int64", v.Text)
+	}
+
+	return n.Int64(), nil
+}
+
+func (v *JsonNumber) Uint64() (uint64, error) {
+	if !isJSONNumber(v.Text) {
+		return 0, fmt.Errorf("%q is not a valid JSON number", v.Text)
+	} else if n, err := strconv.ParseUint(v.Text, 10, 64); err == nil {
+		return n, nil
+	}
+
+	neg, digits, exp, err := v.decompose()
+
+	if err != nil {
+		return 0, err
+	} else if neg {
+		return 0, fmt.Errorf("number %s is negative", v.Text)
+	} else if exp >= 0 && int64(len(digits))+exp > 20 {
+		return 0, fmt.Errorf("number %s overflows uint64", v.Text)
+	}
+
+	n, err := v.BigInt()
+
+	if err != nil {
+		return 0, err
+	} else if !n.IsUint64() {
+		return 0, fmt.Errorf("number %s overflows uint64", v.Text)
+	}
+
+	return n.Uint64(), nil
+}
+
+// Float64 returns v as a float64.
+// Numbers outside the float64 range, nonzero numbers that round to zero, and numbers
+// whose float64 does not format back to the same value (lost precision) are errors.
+func (v *JsonNumber) Float64() (float64, error) {
+	if !isJSONNumber(v.Text) {
+		return 0, fmt.Errorf("%q is not a valid JSON number", v.Text)
+	}
+
+	f, err := strconv.ParseFloat(v.Text, 64)
+
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("number %s is out of float64 range", v.Text)
+	} else if err != nil {
+		return 0, err
+	} else if f == 0 && normalizeNumber(v.Text) != "0" {
+		return 0, fmt.Errorf("number %s underflows float64", v.Text)
+	} else if normalizeNumber(strconv.FormatFloat(f, 'e', -1, 64)) != normalizeNumber(v.Text) {
+		return 0, fmt.Errorf("number %s cannot be represented exactly as float64", v.Text)
+	}
+
+	return f, nil
+}
+
+// BigFloat returns v with enough mantissa bits to hold an integer of as many decimal digits exactly.
+func (v *JsonNumber) BigFloat() (*big.Float, error) {
+	_, digits, _, err := v.decompose()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// log2(10) < 3.33, so 4 bits per digit is always enough.
+	prec := uint(max(64, 4*len(digits)))
+	f, _, err := big.ParseFloat(v.Text, 10, prec, big.ToNearestEven)
+
+	if err != nil {
+		return nil, fmt.Errorf("number %s: %w", v.Text, err)
+	}
+
+	return f, nil
+}
+
+func (v *JsonNumber) Rat() (*big.Rat, error) {
+	neg, digits, exp, err := v.decompose()
+
+	if err != nil {
+		return nil, err
+	} else if exp > maxExactExponent || exp < -maxExactExponent {
+		return nil, fmt.Errorf("number %s is too large to convert exactly", v.Text)
+	}
+
+	n, ok := new(big.Int).SetString("0"+digits, 10)
+
+	if !ok {
+		return nil, fmt.Errorf("%q is not a valid JSON number", v.Text)
+	}
+
+	if neg {
+		n.Neg(n)
+	}
+
+	if exp >= 0 {
+		return new(big.Rat).SetInt(n.Mul(n, pow10(exp))), nil
+	}
+
+	return new(big.Rat).SetFrac(n, pow10(-exp)), nil
+}
@@ -0,0 +1,176 @@
+package jsonast_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumber_Int64(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int64
+		err      string
+	}{
+		{text: "0", expected: 0},
+		{text: "-9223372036854775808", expected: -9223372036854775808},
+		{text: "9223372036854775807", expected: 9223372036854775807},
+		{text: "1e3", expected: 1000},
+		{text: "1.5e1", expected: 15},
+		{text: "-0.0", expected: 0},
+		{text: "9223372036854775808", err: "number 9223372036854775808 overflows int64"},
+		{text: "1e19", err: "number 1e19 overflows int64"},
+		{text: "1e1000000000", err: "number 1e1000000000 overflows int64"},
+		{text: "1.5", err: "number 1.5 is not an integer"},
+		{text: "1e-1", err: "number 1e-1 is not an integer"},
+		{text: "abc", err: `"abc" is not a valid JSON number`},
+		{text: "+1", err: `"+1" is not a valid JSON number`},
+		{text: "01", err: `"01" is not a valid JSON number`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			n, err := vnum(tt.text).Int64()
+
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, n)
+			}
+		})
+	}
+}
+
+func TestNumber_Uint64(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected uint64
+		err      string
+	}{
+		{text: "18446744073709551615", expected: 18446744073709551615},
+		{text: "12345678901234567e2", expected: 1234567890123456700},
+		{text: "-0", expected: 0},
+		{text: "18446744073709551616", err: "number 18446744073709551616 overflows uint64"},
+		{text: "-1", err: "number -1 is negative"},
+		{text: "0.5", err: "number 0.5 is not an integer"},
+		{text: "+1", err: `"+1" is not a valid JSON number`},
+		{text: "007", err: `"007" is not a valid JSON number`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			n, err := vnum(tt.text).Uint64()
+
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, n)
+			}
+		})
+	}
+}
+
+func TestNumber_Float64(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected float64
+		err      string
+	}{
+		{text: "1.5", expected: 1.5},
+		{text: "-1e-300", expected: -1e-300},
+		{text: "0e-999", expected: 0},
+		{text: "0.1", expected: 0.1},
+		{text: "-0", expected: 0},
+		{text: "9007199254740992", expected: 9007199254740992},
+		{text: "1.50e2", expected: 150},
+		{text: "9007199254740993", err: "number 9007199254740993 cannot be represented exactly as float64"},
+		{text: "0.12345678901234567890", err: "number 0.12345678901234567890 cannot be represented exactly as float64"},
+		{text: "1e400", err: "number 1e400 is out of float64 range"},
+		{text: "1e-400", err: "number 1e-400 underflows float64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			f, err := vnum(tt.text).Float64()
+
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, f)
+			}
+		})
+	}
+}
+
+func TestNumber_BigInt(t *testing.T) {
+	n, err := vnum("-123456789012345678901234567890").BigInt()
+	assert.NoError(t, err)
+	assert.Equal(t, "-123456789012345678901234567890", n.String())
+
+	n, err = vnum("12.5e30").BigInt()
+	assert.NoError(t, err)
+	assert.Equal(t, "12500000000000000000000000000000", n.String())
+
+	_, err = vnum("1e100000").BigInt()
+	assert.EqualError(t, err, "number 1e100000 is too large to convert exactly")
+}
+
+func TestNumber_BigFloat(t *testing.T) {
+	f, err := vnum("9007199254740993").BigFloat()
+	assert.NoError(t, err)
+	i, acc := f.Int(nil)
+	assert.Equal(t, big.Exact, acc)
+	assert.Equal(t, "9007199254740993", i.String())
+
+	f, err = vnum("-1.5e-400").BigFloat()
+	assert.NoError(t, err)
+	assert.Equal(t, "-1.5e-400", f.Text('g', 10))
+}
+
+func TestNumber_Rat(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"0", "0/1"},
+		{"0.1", "1/10"},
+		{"-1.25", "-5/4"},
+		{"25e-2", "1/4"},
+		{"1E3", "1000/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			r, err := vnum(tt.text).Rat()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, r.String())
+		})
+	}
+
+	_, err := vnum("1e-100000").Rat()
+	assert.EqualError(t, err, "number 1e-100000 is too large to convert exactly")
+}
+
+func TestNumber_Malformed(t *testing.T) {
+	for _, text := range []string{"1 ", "1e", "--1", "1.2.3", "1e5x", ""} {
+		t.Run(text, func(t *testing.T) {
+			n := vnum(text)
+			_, err := n.Int64()
+			assert.Error(t, err)
+			_, err = n.Uint64()
+			assert.Error(t, err)
+			_, err = n.Float64()
+			assert.Error(t, err)
+			_, err = n.BigInt()
+			assert.Error(t, err)
+			_, err = n.BigFloat()
+			assert.Error(t, err)
+			_, err = n.Rat()
+			assert.Error(t, err)
+		})
+	}
+}
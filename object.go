@@ -0,0 +1,153 @@
+package jsonast
+
+import (
+	"fmt"
+	"slices"
+)
+
+// BuildIndex builds a key index that speeds up Get, Has, Set and Lookup on large objects.
+// Objects have no index unless BuildIndex is called, and read-only methods never modify it,
+// so an indexed object can be shared between goroutines.
+// The helpers on JsonObject keep the index up to date, but direct edits to Members
+// or to member keys invalidate it: call BuildIndex again after making them.
+// Clone does not copy the index.
+func (v *JsonObject) BuildIndex() {
+	v.index = v.lastIndexes()
+}
+
+// lastIndexes maps each key to the position of its last member without touching the index.
+func (v *JsonObject) lastIndexes() map[string]int {
+	idx := make(map[string]int, len(v.Members))
+
+	for i, m := range v.Members {
+		idx[m.Key] = i
+	}
+
+	return idx
+}
+
+// lookupIndex returns the position of the last member named key, or -1 if there is none.
+// It reports false when the object has no index or the indexed member no longer matches.
+func (v *JsonObject) lookupIndex(key string) (int, bool) {
+	if v.index == nil {
+		return 0, false
+	}
+
+	i, ok := v.index[key]
+
+	if !ok {
+		return -1, true
+	} else if i >= len(v.Members) || v.Members[i].Key != key {
+		return 0, false
+	}
+
+	return i, true
+}
+
+func (v *JsonObject) rebuildIndex() {
+	if v.index != nil {
+		v.BuildIndex()
+	}
+}
+
+// Get returns the value of key. When keys are duplicated, the last member wins.
+func (v *JsonObject) Get(key string) (*JsonValue, bool) {
+	if _, m := v.lastMember(key); m != nil {
+		return m.Value, true
+	}
+
+	return nil, false
+}
+
+func (v *JsonObject) Has(key string) bool {
+	_, m := v.lastMember(key)
+	return m != nil
+}
+
+// Set replaces the value of key in place, or appends a new member if key is absent.
+func (v *JsonObject) Set(key string, value *JsonValue) {
+	if _, m := v.lastMember(key); m != nil {
+		m.Value = value
+		return
+	}
+
+	v.Members = append(v.Members, &JsonObjectMember{Key: key, Value: value})
+
+	if v.index != nil {
+		v.index[key] = len(v.Members) - 1
+	}
+}
+
+// Delete removes every member named key and reports whether any was removed.
+func (v *JsonObject) Delete(key string) bool {
+	n := len(v.Members)
+	v.Members = slices.DeleteFunc(v.Members, func(m *JsonObjectMember) bool { return m.Key == key })
+	delete(v.OmittableKeys, key)
+
+	if len(v.Members) == n {
+		return false
+	}
+
+	v.rebuildIndex()
+	return true
+}
+
+// Keys returns the distinct keys in order of first appearance.
+func (v *JsonObject) Keys() []string {
+	keys := make([]string, 0, len(v.Members))
+	seen := make(map[string]struct{}, len(v.Members))
+
+	for _, m := range v.Members {
+		if _, ok := seen[m.Key]; !ok {
+			seen[m.Key] = struct{}{}
+			keys = append(keys, m.Key)
+		}
+	}
+
+	return keys
+}
+
+// SortKeys stably sorts members by key. A nil less sorts by byte order.
+func (v *JsonObject) SortKeys(less func(a, b string) bool) {
+	if less == nil {
+		less = func(a, b string) bool { return a < b }
+	}
+
+	slices.SortStableFunc(v.Members, func(a, b *JsonObjectMember) int {
+		if less(a.Key, b.Key) {
+			return -1
+		} else if less(b.Key, a.Key) {
+			return 1
+		}
+
+		return 0
+	})
+
+	v.rebuildIndex()
+}
+
+// Rename changes the key of every member named from to to, keeping their positions.
+func (v *JsonObject) Rename(from string, to string) error {
+	if !v.Has(from) {
+		return fmt.Errorf("key %q not found", from)
+	} else if from == to {
+		return nil
+	} else if v.Has(to) {
+		return fmt.Errorf("key %q already exists", to)
+	}
+
+	for _, m := range v.Members {
+		if m.Key == from {
+			m.Key = to
+			m.RawKey = ""
+		}
+	}
+
+	if _, ok := v.OmittableKeys[from]; ok {
+		delete(v.OmittableKeys, from)
+		v.OmittableKeys[to] = struct{}{}
+	}
+
+	v.rebuildIndex()
+	return nil
+}
@@ -0,0 +1,169 @@
+package jsonast_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func parseObject(t *testing.T, src string) *jsonast.JsonObject {
+	t.Helper()
+	v, err := jsonast.ParseBytes("", []byte(src))
+	require.NoError(t, err)
+	require.True(t, v.IsObject())
+	return v.Object
+}
+
+func marshalObject(t *testing.T, o *jsonast.JsonObject) string {
+	t.Helper()
+	b, err := (&jsonast.JsonValue{Object: o}).MarshalJSON()
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestObject_Get(t *testing.T) {
+	o := parseObject(t, `{"a":1,"b":2,"a":3}`)
+
+	v, ok := o.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "3", v.Number.Text)
+	assert.True(t, o.Has("b"))
+
+	v, ok = o.Get("c")
+	assert.False(t, ok)
+	assert.Nil(t, v)
+	assert.False(t, o.Has("c"))
+	assert.Equal(t, []string{"a", "b"}, o.Keys())
+}
+
+func TestObject_Set(t *testing.T) {
+	o := parseObject(t, `{"a":1,"b":2,"a":3}`)
+	o.Set("a", jsonast.NewString("x"))
+	o.Set("c", jsonast.NewBool(true))
+	assert.Equal(t, `{"a":1,"b":2,"a":"x","c":true}`, marshalObject(t, o))
+}
+
+func TestObject_Delete(t *testing.T) {
+	o := parseObject(t, `{"a":1,"b":2,"a":3}`)
+	o.OmittableKeys = map[string]struct{}{"a": {}, "b": {}}
+
+	assert.True(t, o.Delete("a"))
+	assert.False(t, o.Delete("a"))
+	assert.Equal(t, `{"b":2}`, marshalObject(t, o))
+	assert.Equal(t, map[string]struct{}{"b": {}}, o.OmittableKeys)
+}
+
+func TestObject_SortKeys(t *testing.T) {
+	o := parseObject(t, `{"b":1,"C":2,"a":3,"b":4}`)
+	o.SortKeys(nil)
+	assert.Equal(t, `{"C":2,"a":3,"b":1,"b":4}`, marshalObject(t, o))
+
+	o.SortKeys(func(a, b string) bool { return strings.ToLower(a) > strings.ToLower(b) })
+	assert.Equal(t, `{"C":2,"b":1,"b":4,"a":3}`, marshalObject(t, o))
+}
+
+func TestObject_Rename(t *testing.T) {
+	o := parseObject(t, `{"a":1,"b":2,"c":3}`)
+	o.OmittableKeys = map[string]struct{}{"c": {}}
+
+	require.NoError(t, o.Rename("c", "d"))
+	require.NoError(t, o.Rename("a", "a"))
+	assert.Equal(t, `{"a":1,"b":2,"d":3}`, marshalObject(t, o))
+	assert.Equal(t, map[string]struct{}{"d": {}}, o.OmittableKeys)
+
+	assert.EqualError(t, o.Rename("x", "y"), `key "x" not found`)
+	assert.EqualError(t, o.Rename("a", "b"), `key "b" already exists`)
+}
+
+func TestObject_DirectEdits(t *testing.T) {
+	o := jsonast.NewObject().Object
+
+	for i := range 20 {
+		o.Set(fmt.Sprintf("k%d", i), jsonast.NewNumberFromInt(int64(i)))
+	}
+
+	v := &jsonast.JsonValue{Object: o}
+	assert.True(t, o.Has("k0"))
+
+	// without an index, direct edits to Members are always visible
+	o.Members[0].Key = "renamed"
+	assert.True(t, o.Has("renamed"))
+	_, err := v.Lookup("/renamed")
+	assert.NoError(t, err)
+
+	o.Members = append(o.Members[1:], jsonast.NewMember("new", jsonast.NewNull()))
+	assert.True(t, o.Has("new"))
+	assert.False(t, o.Has("renamed"))
+}
+
+func TestObject_BuildIndex(t *testing.T) {
+	o := jsonast.NewObject().Object
+
+	for i := range 100 {
+		o.Set(fmt.Sprintf("k%d", i), jsonast.NewNumberFromInt(int64(i)))
+	}
+
+	o.BuildIndex()
+	v, ok := o.Get("k42")
+	require.True(t, ok)
+	assert.Equal(t, "42", v.Number.Text)
+	assert.False(t, o.Has("k100"))
+
+	// the index follows changes made through the helpers
+	o.Set("k100", jsonast.NewNull())
+	assert.True(t, o.Has("k100"))
+	assert.True(t, o.Delete("k0"))
+	assert.False(t, o.Has("k0"))
+	require.NoError(t, o.Rename("k1", "one"))
+	assert.False(t, o.Has("k1"))
+	v, ok = o.Get("one")
+	require.True(t, ok)
+	assert.Equal(t, "1", v.Number.Text)
+	o.SortKeys(nil)
+	v, ok = o.Get("k99")
+	require.True(t, ok)
+	assert.Equal(t, "99", v.Number.Text)
+
+	// direct edits need a rebuild
+	o.Members = append(o.Members, jsonast.NewMember("appended", jsonast.NewNull()))
+	o.BuildIndex()
+	assert.True(t, o.Has("appended"))
+
+	// duplicate keys resolve to the last member
+	o.Members = append(o.Members, jsonast.NewMember("k42", jsonast.NewBool(true)))
+	o.BuildIndex()
+	v, ok = o.Get("k42")
+	require.True(t, ok)
+	assert.True(t, v.IsTrue())
+}
+
+func TestObject_ConcurrentReads(t *testing.T) {
+	o := jsonast.NewObject().Object
+
+	for i := range 20 {
+		o.Set(fmt.Sprintf("k%d", i), jsonast.NewNumberFromInt(int64(i)))
+	}
+
+	for _, index := range []bool{false, true} {
+		if index {
+			o.BuildIndex()
+		}
+
+		var wg sync.WaitGroup
+
+		for range 4 {
+			wg.Go(func() {
+				for i := range 20 {
+					o.Has(fmt.Sprintf("k%d", i))
+				}
+			})
+		}
+
+		wg.Wait()
+	}
+}
@@ -0,0 +1,21 @@
+package jsonast
+
+type DuplicateKeyPolicy int
+
+const (
+	DuplicateKeysKeepAll DuplicateKeyPolicy = iota
+	DuplicateKeysError
+	DuplicateKeysKeepFirst
+	DuplicateKeysKeepLast
+)
+
+// Limits of zero are unlimited.
+type ParseOptions struct {
+	DuplicateKeys   DuplicateKeyPolicy
+	MaxDepth        int
+	MaxInputBytes   int
+	MaxStringLength int
+	MaxNumberLength int
+	MaxMembers      int
+	MaxElements     int
+}
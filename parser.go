@@ -1,9 +1,12 @@
 package jsonast
 
 import (
+	"bytes"
+	"errors"
 	"io"
 
 	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 var (
@@ -44,6 +47,7 @@ func (v *JsonNumber) UnmarshalText(text []byte) error {
 type JsonString struct {
 	nullable
 	Text string
+	Raw  string
 }
 
 func (v *JsonString) UnmarshalText(text []byte) error {
@@ -51,19 +55,28 @@ func (v *JsonString) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// JsonInvalid is a placeholder for a value that could not be parsed by ParseTolerant.
+type JsonInvalid struct {
+	notnullable
+	Err *ParseError
+}
+
 type ValueType interface {
 	UnionType(*JsonValue) *JsonValue
 	Nullable() bool
 }
 
 type JsonValue struct {
-	False  *JsonFalse  `parser:"@false |"`
-	Null   *JsonNull   `parser:"@null |"`
-	True   *JsonTrue   `parser:"@true |"`
-	Object *JsonObject `parser:"@@ |"`
-	Array  *JsonArray  `parser:"@@ |"`
-	Number *JsonNumber `parser:"@number |"`
-	String *JsonString `parser:"@string"`
+	Pos     lexer.Position
+	False   *JsonFalse  `parser:"@false |"`
+	Null    *JsonNull   `parser:"@null |"`
+	True    *JsonTrue   `parser:"@true |"`
+	Object  *JsonObject `parser:"@@ |"`
+	Array   *JsonArray  `parser:"@@ |"`
+	Number  *JsonNumber `parser:"@number |"`
+	String  *JsonString `parser:"@string"`
+	Invalid *JsonInvalid
+	Syntax  *ValueSyntax
 }
 
 func (v *JsonValue) Value() ValueType {
@@ -81,6 +94,8 @@ func (v *JsonValue) Value() ValueType {
 		return v.Number
 	} else if v.String != nil {
 		return v.String
+	} else if v.Invalid != nil {
+		return v.Invalid
 	} else {
 		return nil
 	}
@@ -114,15 +129,33 @@ func (v *JsonValue) IsString() bool {
 	return v.String != nil
 }
 
+func (v *JsonValue) IsInvalid() bool {
+	return v.Invalid != nil
+}
+
+func (v *JsonValue) Bool() (bool, error) {
+	if v.True != nil {
+		return true, nil
+	} else if v.False != nil {
+		return false, nil
+	}
+
+	return false, errors.New("value is not a boolean")
+}
+
 type JsonObject struct {
 	notnullable
 	Members       []*JsonObjectMember `parser:"'{' @@* '}'"`
 	OmittableKeys map[string]struct{}
+	index         map[string]int
 }
 
 type JsonObjectMember struct {
-	Key   string     `parser:"@string"`
-	Value *JsonValue `parser:"@@"`
+	Pos    lexer.Position
+	Key    string `parser:"@string"`
+	RawKey string
+	Value  *JsonValue `parser:"@@"`
+	Syntax *MemberSyntax
 }
 
 type JsonArray struct {
@@ -135,21 +168,86 @@ func (v *JsonArray) Len() int {
 }
 
 func ParseBytes(filename string, src []byte) (*JsonValue, error) {
-	v, err := jsonParser.ParseBytes(filename, src)
+	return Parse(filename, bytes.NewReader(src))
+}
+
+// Parse parses a single JSON value from r. An empty filename defaults to the name of r, if it has one.
+func Parse(filename string, r io.Reader) (*JsonValue, error) {
+	return ParseWithOptions(filename, r, nil)
+}
+
+func ParseBytesWithOptions(filename string, src []byte, opts *ParseOptions) (*JsonValue, error) {
+	return ParseWithOptions(filename, bytes.NewReader(src), opts)
+}
+
+func ParseWithOptions(filename string, r io.Reader, opts *ParseOptions) (*JsonValue, error) {
+	if filename == "" {
+		filename = lexer.NameOfReader(r)
+	}
+
+	pos := lexer.Position{
+		Filename: filename,
+		Line:     1,
+		Column:   1,
+	}
+
+	v, err := parseAt(pos, r, opts)
 
 	if err != nil {
 		return nil, err
 	}
 
+	return applyParseOptions(v, opts)
+}
+
+func applyParseOptions(v *JsonValue, opts *ParseOptions) (*JsonValue, error) {
+	if opts == nil {
+		return v, nil
+	}
+
+	if err := applyDuplicateKeyPolicy(v, opts.DuplicateKeys); err != nil {
+		return nil, err
+	}
+
 	return v, nil
 }
 
-func Parse(filename string, r io.Reader) (*JsonValue, error) {
-	v, err := jsonParser.Parse(filename, r)
+func parseAt(pos lexer.Position, r io.Reader, opts *ParseOptions) (*JsonValue, error) {
+	lex := newJsonLexer(pos, r, opts)
+	peeker, err := lexer.Upgrade(lex)
 
 	if err != nil {
-		return nil, err
+		return nil, newParseError(err)
 	}
 
+	v, err := jsonParser.ParseFromLexer(peeker)
+
+	if err != nil {
+		return nil, newParseError(err)
+	}
+
+	raws := lex.raws
+	setRawStrings(v, &raws)
 	return v, nil
 }
+
+func setRawStrings(v *JsonValue, raws *[]string) {
+	next := func() string {
+		raw := (*raws)[0]
+		*raws = (*raws)[1:]
+		return raw
+	}
+
+	if v.IsString() {
+		v.String.Raw = next()
+	} else if v.IsObject() {
+		for _, m := range v.Object.Members {
+			m.RawKey = next()
+			setRawStrings(m.Value, raws)
+		}
+	} else if v.IsArray() {
+		for _, e := range v.Array.Elements {
+			setRawStrings(e, raws)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/alecthomas/participle/v2/lexer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/winebarrel/jsonast"
@@ -526,10 +527,10 @@ func TestParse_OK(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			v, err := jsonast.ParseBytes("", []byte(tt.json))
 			require.NoError(t, err)
-			assert.Equal(t, tt.expected, v)
+			assert.Equal(t, tt.expected, stripSource(v))
 			v, err = jsonast.Parse("", strings.NewReader(tt.json))
 			require.NoError(t, err)
-			assert.Equal(t, tt.expected, v)
+			assert.Equal(t, tt.expected, stripSource(v))
 		})
 	}
 }
@@ -670,3 +671,85 @@ func TestArrayLen(t *testing.T) {
 		assert.Equal(t, tt.expected, v.Len())
 	}
 }
+
+func TestParse_Pos(t *testing.T) {
+	v, err := jsonast.ParseBytes("<filename>", []byte("{\n  \"foo\": [1, \"bar\"],\n\t\"baz\" :null\n}"))
+	require.NoError(t, err)
+
+	assert.Equal(t, lexer.Position{Filename: "<filename>", Offset: 0, Line: 1, Column: 1}, v.Pos)
+	foo := v.Object.Members[0]
+	assert.Equal(t, lexer.Position{Filename: "<filename>", Offset: 4, Line: 2, Column: 3}, foo.Pos)
+	assert.Equal(t, lexer.Position{Filename: "<filename>", Offset: 11, Line: 2, Column: 10}, foo.Value.Pos)
+	assert.Equal(t, lexer.Position{Filename: "<filename>", Offset: 12, Line: 2, Column: 11}, foo.Value.Array.Elements[0].Pos)
+	assert.Equal(t, lexer.Position{Filename: "<filename>", Offset: 15, Line: 2, Column: 14}, foo.Value.Array.Elements[1].Pos)
+	baz := v.Object.Members[1]
+	assert.Equal(t, lexer.Position{Filename: "<filename>", Offset: 24, Line: 3, Column: 2}, baz.Pos)
+	assert.Equal(t, lexer.Position{Filename: "<filename>", Offset: 31, Line: 3, Column: 9}, baz.Value.Pos)
+}
+
+func TestParse_Raw(t *testing.T) {
+	json := `{"caf\u00e9":"a\/b","\ud83d\ude00":["😀","plain","tab\t"]}`
+
+	for _, parse := range []func() (*jsonast.JsonValue, error){
+		func() (*jsonast.JsonValue, error) { return jsonast.ParseBytes("", []byte(json)) },
+		func() (*jsonast.JsonValue, error) { return jsonast.Parse("", strings.NewReader(json)) },
+		func() (*jsonast.JsonValue, error) { v, _ := jsonast.ParseTolerant("", []byte(json)); return v, nil },
+		func() (*jsonast.JsonValue, error) { return jsonast.ParseLossless("", []byte(json)) },
+	} {
+		v, err := parse()
+		require.NoError(t, err)
+
+		caf := v.Object.Members[0]
+		assert.Equal(t, "café", caf.Key)
+		assert.Equal(t, `"caf\u00e9"`, caf.RawKey)
+		assert.Equal(t, "a/b", caf.Value.String.Text)
+		assert.Equal(t, `"a\/b"`, caf.Value.String.Raw)
+
+		smile := v.Object.Members[1]
+		assert.Equal(t, "😀", smile.Key)
+		assert.Equal(t, `"\ud83d\ude00"`, smile.RawKey)
+		elems := smile.Value.Array.Elements
+		assert.Equal(t, "😀", elems[0].String.Text)
+		assert.Equal(t, `"😀"`, elems[0].String.Raw)
+		assert.Equal(t, `"plain"`, elems[1].String.Raw)
+		assert.Equal(t, `"tab\t"`, elems[2].String.Raw)
+	}
+}
+
+func TestMarshalJSON_StringEscapes(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "café/😀", expected: `"café/😀"`},
+		{text: "\"\\\b\f\n\r\t\x01\x1f<>&", expected: `"\"\\\b\f\n\r\t\u0001\u001f<>&"`},
+		{text: "\xff", expected: "\"�\""},
+	}
+
+	for _, tt := range tests {
+		b, err := jsonast.NewString(tt.text).MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, string(b))
+	}
+
+	v, err := jsonast.ParseBytes("", []byte(`["caf\u00e9","café"]`))
+	require.NoError(t, err)
+	assert.Equal(t, `"caf\u00e9"`, v.Array.Elements[0].String.Raw)
+	assert.Equal(t, `"café"`, v.Array.Elements[1].String.Raw)
+	b, err := jsonast.NewString(v.Array.Elements[0].String.Text).MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `"café"`, string(b))
+}
+
+func TestValue_Bool(t *testing.T) {
+	b, err := jsonast.NewBool(true).Bool()
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = jsonast.NewBool(false).Bool()
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	_, err = jsonast.NewNull().Bool()
+	assert.EqualError(t, err, "value is not a boolean")
+}
@@ -0,0 +1,405 @@
+package jsonast
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// PatchError reports the JSON Patch operation that could not be applied.
+type PatchError struct {
+	Index int
+	Op    string
+	Path  string
+	Pos   lexer.Position
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	msg := fmt.Sprintf("operation %d", e.Index)
+
+	if e.Op != "" || e.Path != "" {
+		msg += fmt.Sprintf(" (%s)", strings.TrimSpace(e.Op+" "+e.Path))
+	}
+
+	msg += ": " + e.Err.Error()
+
+	if e.Pos == (lexer.Position{}) {
+		return msg
+	}
+
+	return fmt.Sprintf("%s: %s", e.Pos, msg)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyPatch applies an RFC 6902 JSON Patch document to v.
+// The patch is applied atomically: if any operation fails, v is left unchanged.
+func (v *JsonValue) ApplyPatch(patch *JsonValue) error {
+	if !patch.IsArray() && patch.Pos == (lexer.Position{}) {
+		return errors.New("JSON Patch must be an array of operations")
+	} else if !patch.IsArray() {
+		return fmt.Errorf("%s: JSON Patch must be an array of operations", patch.Pos)
+	}
+
+	doc := v.Clone()
+
+	for i, e := range patch.Array.Elements {
+		op, path, err := applyPatchOp(doc, e)
+
+		if err != nil {
+			return &PatchError{Index: i, Op: op, Path: path, Pos: e.Pos, Err: err}
+		}
+	}
+
+	*v = *doc
+	return nil
+}
+
+func patchField(op *JsonObject, name string) (*JsonValue, bool) {
+	if _, m := op.lastMember(name); m != nil {
+		return m.Value, true
+	}
+
+	return nil, false
+}
+
+func patchString(op *JsonObject, name string) (string, error) {
+	f, ok := patchField(op, name)
+
+	if !ok {
+		return "", fmt.Errorf("missing %q", name)
+	} else if !f.IsString() {
+		return "", fmt.Errorf("%q must be a string", name)
+	}
+
+	return f.String.Text, nil
+}
+
+func applyPatchOp(doc *JsonValue, e *JsonValue) (string, string, error) {
+	if !e.IsObject() {
+		return "", "", errors.New("operation must be an object")
+	}
+
+	op, err := patchString(e.Object, "op")
+
+	if err != nil {
+		return "", "", err
+	}
+
+	path, err := patchString(e.Object, "path")
+
+	if err != nil {
+		return op, "", err
+	}
+
+	value, hasValue := patchField(e.Object, "value")
+
+	switch op {
+	case "add", "replace", "test":
+		if !hasValue {
+			return op, path, errors.New(`missing "value"`)
+		}
+	case "move", "copy":
+		from, err := patchString(e.Object, "from")
+
+		if err != nil {
+			return op, path, err
+		}
+
+		if op == "move" {
+			return op, path, patchMove(doc, from, path)
+		}
+
+		src, err := doc.Lookup(from)
+
+		if err != nil {
+			return op, path, err
+		}
+
+		return op, path, patchAdd(doc, path, src.Clone())
+	}
+
+	switch op {
+	case "add":
+		err = patchAdd(doc, path, value.Clone())
+	case "remove":
+		_, err = patchRemove(doc, path)
+	case "replace":
+		err = doc.Replace(path, value.Clone())
+	case "test":
+		var cur *JsonValue
+
+		if cur, err = doc.Lookup(path); err == nil && !Equal(cur, value, &EqualOptions{Numbers: NumberCompareNumeric}) {
+			err = errors.New("test failed: values are not equal")
+		}
+	default:
+		err = fmt.Errorf("unknown operation %q", op)
+	}
+
+	return op, path, err
+}
+
+// splitParent returns the container that path points into and the last reference token.
+func splitParent(doc *JsonValue, path string) (*JsonValue, string, error) {
+	tokens, err := splitPointer(path)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	parent, err := doc.Lookup(path[:strings.LastIndex(path, "/")])
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return parent, tokens[len(tokens)-1], nil
+}
+
+func patchAdd(doc *JsonValue, path string, nv *JsonValue) error {
+	if path == "" {
+		*doc = *nv
+		return nil
+	}
+
+	parent, token, err := splitParent(doc, path)
+
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case parent.IsObject():
+		if _, m := parent.Object.lastMember(token); m != nil {
+			m.Value = nv
+		} else {
+			parent.Object.Members = append(parent.Object.Members, &JsonObjectMember{Key: token, Value: nv})
+		}
+	case parent.IsArray():
+		i := parent.Array.Len()
+
+		if token != "-" {
+			if i, err = arrayIndex(token, parent.Array.Len()+1); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+
+		parent.Array.Elements = slices.Insert(parent.Array.Elements, i, nv)
+		insertComma(parent.Syntax, i)
+	default:
+		return fmt.Errorf("%s: cannot add a member to a scalar", path)
+	}
+
+	return nil
+}
+
+func patchRemove(doc *JsonValue, path string) (*JsonValue, error) {
+	if path == "" {
+		return nil, errors.New("cannot remove the root value")
+	}
+
+	parent, token, err := splitParent(doc, path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case parent.IsObject():
+		_, m := parent.Object.lastMember(token)
+
+		if m == nil {
+			return nil, fmt.Errorf("%s: key %q not found", path, token)
+		}
+
+		for i := len(parent.Object.Members) - 1; i >= 0; i-- {
+			if parent.Object.Members[i].Key == token {
+				parent.Object.Members = slices.Delete(parent.Object.Members, i, i+1)
+				deleteComma(parent.Syntax, i)
+			}
+		}
+
+		return m.Value, nil
+	case parent.IsArray():
+		i, err := arrayIndex(token, parent.Array.Len())
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		old := parent.Array.Elements[i]
+		parent.Array.Elements = slices.Delete(parent.Array.Elements, i, i+1)
+		deleteComma(parent.Syntax, i)
+		return old, nil
+	default:
+		return nil, fmt.Errorf("%s: cannot remove a member of a scalar", path)
+	}
+}
+
+func patchMove(doc *JsonValue, from string, path string) error {
+	if from == path {
+		_, err := doc.Lookup(from)
+		return err
+	} else if strings.HasPrefix(path, from+"/") {
+		return fmt.Errorf("cannot move %s into its own child", from)
+	}
+
+	v, err := patchRemove(doc, from)
+
+	if err != nil {
+		return err
+	}
+
+	return patchAdd(doc, path, v)
+}
+
+// insertComma and deleteComma keep lossless comma whitespace aligned with the elements.
+func insertComma(syn *ValueSyntax, i int) {
+	if syn != nil && len(syn.Commas) > 0 {
+		syn.Commas = slices.Insert(syn.Commas, min(max(i-1, 0), len(syn.Commas)), "")
+	}
+}
+
+func deleteComma(syn *ValueSyntax, i int) {
+	if syn != nil && len(syn.Commas) > 0 {
+		j := min(max(i-1, 0), len(syn.Commas)-1)
+		syn.Commas = slices.Delete(syn.Commas, j, j+1)
+	}
+}
+
+// GeneratePatch returns an RFC 6902 JSON Patch that turns a into b.
+// Arrays are diffed by longest common subsequence, so unchanged elements are kept in place
+// and the remaining elements are paired up as nested changes before falling back to remove and add.
+func GeneratePatch(a, b *JsonValue) *JsonValue {
+	g := &patchGenerator{ops: []*JsonValue{}}
+	g.generate(a, b, "")
+	return &JsonValue{Array: &JsonArray{Elements: g.ops}}
+}
+
+type patchGenerator struct {
+	ops []*JsonValue
+}
+
+func (g *patchGenerator) emit(op string, path string, value *JsonValue) {
+	obj := &JsonObject{Members: []*JsonObjectMember{
+		{Key: "op", Value: &JsonValue{String: &JsonString{Text: op}}},
+		{Key: "path", Value: &JsonValue{String: &JsonString{Text: path}}},
+	}}
+
+	if value != nil {
+		value = value.Clone()
+		clearSyntax(value)
+		obj.Members = append(obj.Members, &JsonObjectMember{Key: "value", Value: value})
+	}
+
+	g.ops = append(g.ops, &JsonValue{Object: obj})
+}
+
+func (g *patchGenerator) generate(a, b *JsonValue, path string) {
+	switch {
+	case a.IsObject() && b.IsObject():
+		ai, bi := a.Object.lastIndexes(), b.Object.lastIndexes()
+
+		for i, m := range a.Object.Members {
+			if ai[m.Key] != i {
+				continue
+			}
+
+			if j, ok := bi[m.Key]; ok {
+				g.generate(m.Value, b.Object.Members[j].Value, appendPointer(path, m.Key))
+			} else {
+				g.emit("remove", appendPointer(path, m.Key), nil)
+			}
+		}
+
+		for i, m := range b.Object.Members {
+			if bi[m.Key] != i {
+				continue
+			}
+
+			if _, ok := ai[m.Key]; !ok {
+				g.emit("add", appendPointer(path, m.Key), m.Value)
+			}
+		}
+	case a.IsArray() && b.IsArray():
+		g.generateArray(a.Array.Elements, b.Array.Elements, path)
+	case !Equal(a, b, nil):
+		g.emit("replace", path, b)
+	}
+}
+
+func (g *patchGenerator) generateArray(x, y []*JsonValue, path string) {
+	// Common prefixes and suffixes need no operations and keep the table small.
+	pre := 0
+
+	for pre < len(x) && pre < len(y) && Equal(x[pre], y[pre], nil) {
+		pre++
+	}
+
+	suf := 0
+
+	for suf < len(x)-pre && suf < len(y)-pre && Equal(x[len(x)-1-suf], y[len(y)-1-suf], nil) {
+		suf++
+	}
+
+	x, y = x[pre:len(x)-suf], y[pre:len(y)-suf]
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if Equal(x[i], y[j], nil) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// k is the index of x[i] in the array as patched so far.
+	i, j, k := 0, 0, pre
+
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && Equal(x[i], y[j], nil):
+			i, j, k = i+1, j+1, k+1
+		case i < len(x) && j < len(y) && lcs[i][j] == lcs[i+1][j+1]:
+			// Neither element is kept, so change one into the other in place.
+			g.generate(x[i], y[j], appendIndexPointer(path, k))
+			i, j, k = i+1, j+1, k+1
+		case j == len(y) || i < len(x) && lcs[i+1][j] >= lcs[i][j+1]:
+			g.emit("remove", appendIndexPointer(path, k), nil)
+			i++
+		default:
+			g.emit("add", appendIndexPointer(path, k), y[j])
+			j, k = j+1, k+1
+		}
+	}
+}
+
+func clearSyntax(v *JsonValue) {
+	v.Syntax = nil
+
+	switch {
+	case v.Object != nil:
+		for _, m := range v.Object.Members {
+			m.Syntax = nil
+			clearSyntax(m.Value)
+		}
+	case v.Array != nil:
+		for _, e := range v.Array.Elements {
+			clearSyntax(e)
+		}
+	}
+}
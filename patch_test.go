@@ -0,0 +1,293 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+	}{
+		{
+			name:     "add member",
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"add","path":"/baz","value":"qux"}]`,
+			expected: `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			name:     "add element",
+			doc:      `{"foo":["bar","baz"]}`,
+			patch:    `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			expected: `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:     "append element",
+			doc:      `{"foo":["bar"]}`,
+			patch:    `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			expected: `{"foo":["bar",["abc","def"]]}`,
+		},
+		{
+			name:     "add replaces member",
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"add","path":"/foo","value":1}]`,
+			expected: `{"foo":1}`,
+		},
+		{
+			name:     "add root",
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"add","path":"","value":[1]}]`,
+			expected: `[1]`,
+		},
+		{
+			name:     "remove",
+			doc:      `{"baz":"qux","foo":["bar","qux","baz"]}`,
+			patch:    `[{"op":"remove","path":"/baz"},{"op":"remove","path":"/foo/1"}]`,
+			expected: `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:     "remove duplicate keys",
+			doc:      `{"a":1,"b":2,"a":3}`,
+			patch:    `[{"op":"remove","path":"/a"}]`,
+			expected: `{"b":2}`,
+		},
+		{
+			name:     "replace",
+			doc:      `{"baz":"qux","foo":"bar"}`,
+			patch:    `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			expected: `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name:     "move",
+			doc:      `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:    `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			expected: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:     "move element",
+			doc:      `{"foo":["all","grass","cows","eat"]}`,
+			patch:    `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			expected: `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			name:     "copy",
+			doc:      `{"a":{"b":[1]}}`,
+			patch:    `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/b/-","value":2}]`,
+			expected: `{"a":{"b":[1]},"c":{"b":[1,2]}}`,
+		},
+		{
+			name:     "test",
+			doc:      `{"baz":"qux","foo":["a",2,"c"],"n":1.0}`,
+			patch:    `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo","value":["a",2,"c"]},{"op":"test","path":"/n","value":1}]`,
+			expected: `{"baz":"qux","foo":["a",2,"c"],"n":1.0}`,
+		},
+		{
+			name:     "escaped path",
+			doc:      `{"a/b":{"m~n":1}}`,
+			patch:    `[{"op":"replace","path":"/a~1b/m~0n","value":2}]`,
+			expected: `{"a/b":{"m~n":2}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := jsonast.ParseBytes("doc.json", []byte(tt.doc))
+			require.NoError(t, err)
+			patch, err := jsonast.ParseBytes("patch.json", []byte(tt.patch))
+			require.NoError(t, err)
+			require.NoError(t, doc.ApplyPatch(patch))
+			b, err := doc.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+		})
+	}
+}
+
+func TestApplyPatch_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		index    int
+		expected string
+	}{
+		{
+			name:     "test failed",
+			patch:    `[{"op":"add","path":"/b","value":2},{"op":"test","path":"/a","value":"1"}]`,
+			index:    1,
+			expected: `patch.json:1:37: operation 1 (test /a): test failed: values are not equal`,
+		},
+		{
+			name:     "missing path",
+			patch:    `[{"op":"remove","path":"/x"}]`,
+			index:    0,
+			expected: `patch.json:1:2: operation 0 (remove /x): /x: key "x" not found`,
+		},
+		{
+			name:     "index out of range",
+			patch:    `[{"op":"add","path":"/c/3","value":0}]`,
+			index:    0,
+			expected: `patch.json:1:2: operation 0 (add /c/3): /c/3: array index 3 out of range`,
+		},
+		{
+			name:     "unknown op",
+			patch:    `[{"op":"frob","path":""}]`,
+			index:    0,
+			expected: `patch.json:1:2: operation 0 (frob): unknown operation "frob"`,
+		},
+		{
+			name:     "missing value",
+			patch:    `[{"op":"replace","path":"/a"}]`,
+			index:    0,
+			expected: `patch.json:1:2: operation 0 (replace /a): missing "value"`,
+		},
+		{
+			name:     "move into child",
+			patch:    `[{"op":"move","from":"/c","path":"/c/0"}]`,
+			index:    0,
+			expected: `patch.json:1:2: operation 0 (move /c/0): cannot move /c into its own child`,
+		},
+		{
+			name:     "not an object",
+			patch:    `[1]`,
+			index:    0,
+			expected: `patch.json:1:2: operation 0: operation must be an object`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := jsonast.ParseBytes("doc.json", []byte(`{"a":1,"c":[1]}`))
+			require.NoError(t, err)
+			patch, err := jsonast.ParseBytes("patch.json", []byte(tt.patch))
+			require.NoError(t, err)
+
+			err = doc.ApplyPatch(patch)
+			var perr *jsonast.PatchError
+			require.ErrorAs(t, err, &perr)
+			assert.Equal(t, tt.index, perr.Index)
+			assert.EqualError(t, err, tt.expected)
+
+			// the document is untouched when any operation fails
+			b, err := doc.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, `{"a":1,"c":[1]}`, string(b))
+		})
+	}
+}
+
+func TestApplyPatch_ErrorWithoutSource(t *testing.T) {
+	doc, err := jsonast.NewObjectBuilder().Int("a", 1).Build()
+	require.NoError(t, err)
+
+	err = doc.ApplyPatch(jsonast.NewArray(jsonast.NewNumberFromInt(1)))
+	assert.EqualError(t, err, "operation 0: operation must be an object")
+
+	op, err := jsonast.NewObjectBuilder().String("op", "remove").String("path", "/x").Build()
+	require.NoError(t, err)
+	err = doc.ApplyPatch(jsonast.NewArray(op))
+	assert.EqualError(t, err, `operation 0 (remove /x): /x: key "x" not found`)
+
+	err = doc.ApplyPatch(jsonast.NewObject())
+	assert.EqualError(t, err, "JSON Patch must be an array of operations")
+}
+
+func TestApplyPatch_Lossless(t *testing.T) {
+	doc, err := jsonast.ParseLossless("doc.json", []byte("[ 1 , 2 ,3 ]"))
+	require.NoError(t, err)
+	patch, err := jsonast.ParseBytes("patch.json", []byte(`[{"op":"remove","path":"/1"},{"op":"add","path":"/-","value":4}]`))
+	require.NoError(t, err)
+	require.NoError(t, doc.ApplyPatch(patch))
+	b, err := doc.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "[ 1 ,3,4 ]", string(b))
+}
+
+func TestGeneratePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected string
+	}{
+		{
+			name:     "equal",
+			a:        `{"a":[1,2]}`,
+			b:        `{"a":[1,2]}`,
+			expected: `[]`,
+		},
+		{
+			name:     "object",
+			a:        `{"a":1,"b":{"c":true},"d":null}`,
+			b:        `{"a":2,"b":{"c":true,"e":"x"},"f/g":[]}`,
+			expected: `[{"op":"replace","path":"/a","value":2},{"op":"add","path":"/b/e","value":"x"},{"op":"remove","path":"/d"},{"op":"add","path":"/f~1g","value":[]}]`,
+		},
+		{
+			name:     "array shrink",
+			a:        `[1,2,3,4]`,
+			b:        `[1,5]`,
+			expected: `[{"op":"replace","path":"/1","value":5},{"op":"remove","path":"/2"},{"op":"remove","path":"/2"}]`,
+		},
+		{
+			name:     "array insert at front",
+			a:        `[1,2,3]`,
+			b:        `[0,1,2,3]`,
+			expected: `[{"op":"add","path":"/0","value":0}]`,
+		},
+		{
+			name:     "array remove from middle",
+			a:        `["a","b","c","d"]`,
+			b:        `["a","c","d"]`,
+			expected: `[{"op":"remove","path":"/1"}]`,
+		},
+		{
+			name:     "array mixed edits",
+			a:        `[1,2,3,4,5]`,
+			b:        `[0,1,3,{"x":4},5,6]`,
+			expected: `[{"op":"add","path":"/0","value":0},{"op":"remove","path":"/2"},{"op":"replace","path":"/3","value":{"x":4}},{"op":"add","path":"/5","value":6}]`,
+		},
+		{
+			name:     "array nested change",
+			a:        `[{"id":1,"v":"a"},{"id":2,"v":"b"}]`,
+			b:        `[{"id":1,"v":"a"},{"id":2,"v":"c"}]`,
+			expected: `[{"op":"replace","path":"/1/v","value":"c"}]`,
+		},
+		{
+			name:     "array grow",
+			a:        `[1]`,
+			b:        `[1,{"x":1},2]`,
+			expected: `[{"op":"add","path":"/1","value":{"x":1}},{"op":"add","path":"/2","value":2}]`,
+		},
+		{
+			name:     "root",
+			a:        `{}`,
+			b:        `"s"`,
+			expected: `[{"op":"replace","path":"","value":"s"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := jsonast.ParseBytes("a.json", []byte(tt.a))
+			require.NoError(t, err)
+			b, err := jsonast.ParseBytes("b.json", []byte(tt.b))
+			require.NoError(t, err)
+
+			patch := jsonast.GeneratePatch(a, b)
+			out, err := patch.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+
+			// the generated patch survives a round trip through Parse
+			patch, err = jsonast.ParseBytes("patch.json", out)
+			require.NoError(t, err)
+			require.NoError(t, a.ApplyPatch(patch))
+			assert.Empty(t, jsonast.Diff(a, b))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package jsonast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func appendPointer(ptr string, token string) string {
+	return ptr + "/" + pointerEscaper.Replace(token)
+}
+
+func appendIndexPointer(ptr string, i int) string {
+	return ptr + "/" + strconv.Itoa(i)
+}
+
+func splitPointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	} else if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with '/'", ptr)
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+
+	for i, t := range tokens {
+		tokens[i] = pointerUnescaper.Replace(t)
+	}
+
+	return tokens, nil
+}
+
+func arrayIndex(token string, length int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	i, err := strconv.Atoi(token)
+
+	if err != nil || i >= length {
+		return 0, fmt.Errorf("array index %s out of range", token)
+	}
+
+	return i, nil
+}
+
+func (v *JsonObject) lastMember(key string) (int, *JsonObjectMember) {
+	if i, ok := v.lookupIndex(key); ok {
+		if i < 0 {
+			return -1, nil
+		}
+
+		return i, v.Members[i]
+	}
+
+	for i := len(v.Members) - 1; i >= 0; i-- {
+		if v.Members[i].Key == key {
+			return i, v.Members[i]
+		}
+	}
+
+	return -1, nil
+}
+
+// Lookup resolves an RFC 6901 JSON Pointer against v.
+// When an object has duplicate keys, the last member wins.
+func (v *JsonValue) Lookup(ptr string) (*JsonValue, error) {
+	tokens, err := splitPointer(ptr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cur := v
+
+	for _, t := range tokens {
+		switch {
+		case cur.IsObject():
+			_, m := cur.Object.lastMember(t)
+
+			if m == nil {
+				return nil, fmt.Errorf("%s: key %q not found", ptr, t)
+			}
+
+			cur = m.Value
+		case cur.IsArray():
+			i, err := arrayIndex(t, cur.Array.Len())
+
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", ptr, err)
+			}
+
+			cur = cur.Array.Elements[i]
+		default:
+			return nil, fmt.Errorf("%s: cannot index into a scalar with %q", ptr, t)
+		}
+	}
+
+	return cur, nil
+}
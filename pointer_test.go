@@ -0,0 +1,67 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestLookup(t *testing.T) {
+	v, err := jsonast.ParseBytes("<filename>", []byte(`{"foo":["bar","baz"],"":0,"a/b":1,"c%d":2,"m~n":3,"dup":4,"dup":5,"obj":{"x":[{"y":true}]}}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		ptr      string
+		expected *jsonast.JsonValue
+	}{
+		{"", v},
+		{"/foo", v.Object.Members[0].Value},
+		{"/foo/0", &jsonast.JsonValue{String: vstr("bar")}},
+		{"/foo/1", &jsonast.JsonValue{String: vstr("baz")}},
+		{"/", &jsonast.JsonValue{Number: vnum("0")}},
+		{"/a~1b", &jsonast.JsonValue{Number: vnum("1")}},
+		{"/c%d", &jsonast.JsonValue{Number: vnum("2")}},
+		{"/m~0n", &jsonast.JsonValue{Number: vnum("3")}},
+		{"/dup", &jsonast.JsonValue{Number: vnum("5")}},
+		{"/obj/x/0/y", &jsonast.JsonValue{True: vtrue()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ptr, func(t *testing.T) {
+			node, err := v.Lookup(tt.ptr)
+			require.NoError(t, err)
+
+			if tt.ptr == "" || tt.ptr == "/foo" {
+				assert.Same(t, tt.expected, node)
+			} else {
+				assert.Equal(t, tt.expected, stripSource(node))
+			}
+		})
+	}
+}
+
+func TestLookup_Err(t *testing.T) {
+	v, err := jsonast.ParseBytes("<filename>", []byte(`{"foo":["bar"],"n":1}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		ptr string
+		err string
+	}{
+		{"foo", `invalid JSON pointer "foo": must be empty or start with '/'`},
+		{"/bar", `/bar: key "bar" not found`},
+		{"/foo/1", `/foo/1: array index 1 out of range`},
+		{"/foo/01", `/foo/01: invalid array index "01"`},
+		{"/foo/-", `/foo/-: invalid array index "-"`},
+		{"/n/0", `/n/0: cannot index into a scalar with "0"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ptr, func(t *testing.T) {
+			_, err := v.Lookup(tt.ptr)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
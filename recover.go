@@ -0,0 +1,313 @@
+package jsonast
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParseTolerant parses src without stopping at the first error.
+// Malformed values are replaced with Invalid nodes and parsing resumes at the next ',', '}' or ']'.
+// The returned tree is never nil; every problem found is reported in diagnostics.
+func ParseTolerant(filename string, src []byte) (*JsonValue, []*ParseError) {
+	p := &tolerantParser{scanner: newScanner(filename, src)}
+	v := p.parseDocument()
+	return v, p.diags
+}
+
+type tolerantParser struct {
+	scanner  *scanner
+	tok      *scanToken
+	diags    []*ParseError
+	lossless bool
+}
+
+// parseDocument parses the root value and reports anything after it once.
+func (p *tolerantParser) parseDocument() *JsonValue {
+	p.advance()
+	v := p.parseValue()
+
+	if p.tok.kind == scanEOF {
+		return v
+	}
+
+	// A container or the root value may already have reported the token it stopped at.
+	if n := len(p.diags); n == 0 || p.diags[n-1].Pos != p.tok.pos {
+		p.unexpected("")
+	}
+
+	for p.tok.kind != scanEOF {
+		p.advance()
+	}
+
+	return v
+}
+
+func (p *tolerantParser) advance() {
+	p.tok = p.scanner.next()
+}
+
+func (p *tolerantParser) report(category ErrorCategory, tok *scanToken, msg string, expected ...string) *ParseError {
+	err := &ParseError{
+		Category: category,
+		Pos:      tok.pos,
+		Token:    tok.String(),
+		Expected: expected,
+		Msg:      msg,
+	}
+
+	p.diags = append(p.diags, err)
+	return err
+}
+
+func (p *tolerantParser) unexpected(expected string) *ParseError {
+	if p.tok.kind == scanInvalid {
+		return p.report(ErrorCategoryLexical, p.tok, p.tok.err)
+	} else if expected == "" {
+		return p.report(ErrorCategorySyntax, p.tok, fmt.Sprintf("unexpected token %q", p.tok))
+	}
+
+	msg := fmt.Sprintf("unexpected token %q (expected %s)", p.tok, expected)
+
+	if s, err := strconv.Unquote(expected); err == nil {
+		expected = s
+	}
+
+	return p.report(ErrorCategorySyntax, p.tok, msg, expected)
+}
+
+// recover skips to the next ',', '}', ']' or EOF, stepping over nested containers.
+func (p *tolerantParser) recover() {
+	depth := 0
+
+	for p.tok.kind != scanEOF {
+		switch p.tok.kind {
+		case scanBeginObject, scanBeginArray:
+			depth++
+		case scanEndObject, scanEndArray:
+			if depth == 0 {
+				return
+			}
+
+			depth--
+		case scanComma:
+			if depth == 0 {
+				return
+			}
+		}
+
+		p.advance()
+	}
+}
+
+func (p *tolerantParser) invalid(err *ParseError, pos *scanToken) *JsonValue {
+	return &JsonValue{Pos: pos.pos, Invalid: &JsonInvalid{Err: err}}
+}
+
+func (p *tolerantParser) parseValue() *JsonValue {
+	tok := p.tok
+	v := &JsonValue{Pos: tok.pos}
+
+	if p.lossless {
+		v.Syntax = &ValueSyntax{Leading: tok.leading}
+	}
+
+	switch tok.kind {
+	case scanBeginObject:
+		v.Object = p.parseObject(v.Syntax)
+		return v
+	case scanBeginArray:
+		v.Array = p.parseArray(v.Syntax)
+		return v
+	case scanString:
+		text, err := decodeString(tok.raw)
+
+		if err != nil {
+			diag := p.report(ErrorCategoryLexical, tok, err.Error())
+			p.advance()
+			return p.invalid(diag, tok)
+		}
+
+		v.String = &JsonString{Text: text, Raw: tok.raw}
+	case scanNumber:
+		v.Number = &JsonNumber{Text: tok.raw}
+	case scanTrue:
+		v.True = &JsonTrue{}
+	case scanFalse:
+		v.False = &JsonFalse{}
+	case scanNull:
+		v.Null = &JsonNull{}
+	case scanInvalid:
+		diag := p.unexpected("")
+		p.advance()
+		return p.invalid(diag, tok)
+	default:
+		return p.invalid(p.unexpected("value"), tok)
+	}
+
+	p.advance()
+	return v
+}
+
+func (p *tolerantParser) parseObject(syn *ValueSyntax) *JsonObject {
+	obj := &JsonObject{}
+	p.advance()
+
+	if p.tok.kind == scanEndObject {
+		p.close(syn)
+		return obj
+	}
+
+	for {
+		if p.tok.kind == scanEOF || p.tok.kind == scanEndArray {
+			p.unexpected("string")
+			return obj
+		}
+
+		if m := p.parseMember(); m != nil {
+			obj.Members = append(obj.Members, m)
+		}
+
+		switch p.tok.kind {
+		case scanComma:
+			p.comma(syn)
+
+			if p.tok.kind == scanEndObject {
+				p.unexpected(`string`)
+				p.close(syn)
+				return obj
+			}
+		case scanEndObject:
+			p.close(syn)
+			return obj
+		case scanString:
+			p.unexpected(`","`)
+		case scanEOF, scanEndArray:
+			p.unexpected(`"}"`)
+			return obj
+		default:
+			p.unexpected(`","`)
+			p.recover()
+
+			if p.tok.kind == scanEndObject {
+				p.close(syn)
+				return obj
+			} else if p.tok.kind == scanComma {
+				p.advance()
+			}
+		}
+	}
+}
+
+func (p *tolerantParser) parseMember() *JsonObjectMember {
+	keyTok := p.tok
+
+	if keyTok.kind != scanString {
+		p.unexpected("string")
+		p.recover()
+		return nil
+	}
+
+	key, err := decodeString(keyTok.raw)
+
+	if err != nil {
+		p.report(ErrorCategoryLexical, keyTok, err.Error())
+	}
+
+	m := &JsonObjectMember{Pos: keyTok.pos, Key: key, RawKey: keyTok.raw}
+	p.advance()
+
+	if p.lossless {
+		m.Syntax = &MemberSyntax{Leading: keyTok.leading, Colon: p.tok.leading}
+	}
+
+	if p.tok.kind == scanColon {
+		p.advance()
+	} else {
+		diag := p.unexpected(`":"`)
+
+		if !p.startsValue() {
+			p.recover()
+			m.Value = p.invalid(diag, p.tok)
+			return m
+		}
+	}
+
+	m.Value = p.parseValue()
+	return m
+}
+
+func (p *tolerantParser) parseArray(syn *ValueSyntax) *JsonArray {
+	ary := &JsonArray{}
+	p.advance()
+
+	if p.tok.kind == scanEndArray {
+		p.close(syn)
+		return ary
+	}
+
+	for {
+		if p.tok.kind == scanEOF || p.tok.kind == scanEndObject {
+			p.unexpected("value")
+			return ary
+		}
+
+		ary.Elements = append(ary.Elements, p.parseValue())
+
+		switch p.tok.kind {
+		case scanComma:
+			p.comma(syn)
+
+			if p.tok.kind == scanEndArray {
+				p.unexpected("value")
+				p.close(syn)
+				return ary
+			}
+		case scanEndArray:
+			p.close(syn)
+			return ary
+		case scanEOF, scanEndObject:
+			p.unexpected(`"]"`)
+			return ary
+		default:
+			if p.startsValue() {
+				p.unexpected(`","`)
+				continue
+			}
+
+			p.unexpected(`","`)
+			p.recover()
+
+			if p.tok.kind == scanEndArray {
+				p.close(syn)
+				return ary
+			} else if p.tok.kind == scanComma {
+				p.advance()
+			}
+		}
+	}
+}
+
+func (p *tolerantParser) comma(syn *ValueSyntax) {
+	if syn != nil {
+		syn.Commas = append(syn.Commas, p.tok.leading)
+	}
+
+	p.advance()
+}
+
+func (p *tolerantParser) close(syn *ValueSyntax) {
+	if syn != nil {
+		syn.Close = p.tok.leading
+	}
+
+	p.advance()
+}
+
+func (p *tolerantParser) startsValue() bool {
+	switch p.tok.kind {
+	case scanBeginObject, scanBeginArray, scanString, scanNumber, scanTrue, scanFalse, scanNull:
+		return true
+	default:
+		return false
+	}
+}
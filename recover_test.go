@@ -0,0 +1,260 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestParseTolerant_Valid(t *testing.T) {
+	tests := []string{
+		`1`,
+		`-1.5e+3`,
+		`0`,
+		`-0.0E-0`,
+		`12345678901234567890123456789`,
+		`"héllo\n"`,
+		`""`,
+		`"\"\\\/\b\f\n\r\t\u0000\u00e9\ud83d\ude00"`,
+		`"😀 \u2028"`,
+		`true`,
+		`false`,
+		`null`,
+		`{}`,
+		`[]`,
+		" \t\r\n[ ] ",
+		`[[[[]]],[{}],{"":[]}]`,
+		`{"a":1,"a":2,"\u0061":3}`,
+		`{"k\ty":{"x":[1,"2",true,false,null,{"y":-0.5e-10}]}}`,
+		"{\n  \"str\": \"s\",\n  \"num\": 1,\n  \"obj\": {\"t\": true, \"f\": false, \"null\": null},\n  \"ary\": [\"s\", 1, [], {}]\n}\n",
+	}
+
+	for _, json := range tests {
+		t.Run(json, func(t *testing.T) {
+			expected, err := jsonast.ParseBytes("<filename>", []byte(json))
+			require.NoError(t, err)
+			v, diags := jsonast.ParseTolerant("<filename>", []byte(json))
+			assert.Empty(t, diags)
+			assert.Equal(t, expected, v)
+			lossless, err := jsonast.ParseLossless("<filename>", []byte(json))
+			require.NoError(t, err)
+			assert.Equal(t, expected, stripSyntax(lossless))
+		})
+	}
+}
+
+func TestParseTolerant_Recover(t *testing.T) {
+	type diag struct {
+		Pos      string
+		Category jsonast.ErrorCategory
+		Msg      string
+	}
+
+	tests := []struct {
+		name     string
+		json     string
+		expected *jsonast.JsonValue
+		diags    []diag
+	}{
+		{
+			name: "empty",
+			json: ``,
+			expected: &jsonast.JsonValue{
+				Invalid: &jsonast.JsonInvalid{},
+			},
+			diags: []diag{
+				{"f:1:1", jsonast.ErrorCategorySyntax, `unexpected token "<EOF>" (expected value)`},
+			},
+		},
+		{
+			name: "bad values in object",
+			json: `{"a": tru, "b": 2, "c": [1,, 3], "d" 4, "e": }`,
+			expected: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+				{Key: "a", Value: &jsonast.JsonValue{Invalid: &jsonast.JsonInvalid{}}},
+				{Key: "b", Value: &jsonast.JsonValue{Number: vnum("2")}},
+				{Key: "c", Value: &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+					{Number: vnum("1")},
+					{Invalid: &jsonast.JsonInvalid{}},
+					{Number: vnum("3")},
+				}}}},
+				{Key: "d", Value: &jsonast.JsonValue{Number: vnum("4")}},
+				{Key: "e", Value: &jsonast.JsonValue{Invalid: &jsonast.JsonInvalid{}}},
+			}}},
+			diags: []diag{
+				{"f:1:7", jsonast.ErrorCategoryLexical, `invalid literal "tru"`},
+				{"f:1:28", jsonast.ErrorCategorySyntax, `unexpected token "," (expected value)`},
+				{"f:1:38", jsonast.ErrorCategorySyntax, `unexpected token "4" (expected ":")`},
+				{"f:1:46", jsonast.ErrorCategorySyntax, `unexpected token "}" (expected value)`},
+			},
+		},
+		{
+			name: "missing commas and trailing comma",
+			json: `[1 2, {"a": 1 "b": 2,}, 3,]`,
+			expected: &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+				{Number: vnum("1")},
+				{Number: vnum("2")},
+				{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+					{Key: "a", Value: &jsonast.JsonValue{Number: vnum("1")}},
+					{Key: "b", Value: &jsonast.JsonValue{Number: vnum("2")}},
+				}}},
+				{Number: vnum("3")},
+			}}},
+			diags: []diag{
+				{"f:1:4", jsonast.ErrorCategorySyntax, `unexpected token "2" (expected ",")`},
+				{"f:1:15", jsonast.ErrorCategorySyntax, `unexpected token "\"b\"" (expected ",")`},
+				{"f:1:22", jsonast.ErrorCategorySyntax, `unexpected token "}" (expected string)`},
+				{"f:1:27", jsonast.ErrorCategorySyntax, `unexpected token "]" (expected value)`},
+			},
+		},
+		{
+			name: "skip garbage to next member",
+			json: "{\"a\": 1 @ [2, {\"x\": 3}], \"b\": \"unterminated\n, \"c\": 3, 4: 5}",
+			expected: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+				{Key: "a", Value: &jsonast.JsonValue{Number: vnum("1")}},
+				{Key: "b", Value: &jsonast.JsonValue{Invalid: &jsonast.JsonInvalid{}}},
+				{Key: "c", Value: &jsonast.JsonValue{Number: vnum("3")}},
+			}}},
+			diags: []diag{
+				{"f:1:9", jsonast.ErrorCategoryLexical, `invalid character '@'`},
+				{"f:1:31", jsonast.ErrorCategoryLexical, `unterminated string`},
+				{"f:2:11", jsonast.ErrorCategorySyntax, `unexpected token "4" (expected string)`},
+			},
+		},
+		{
+			name: "unclosed containers",
+			json: `{"a": [1, {"b": 2`,
+			expected: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+				{Key: "a", Value: &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+					{Number: vnum("1")},
+					{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+						{Key: "b", Value: &jsonast.JsonValue{Number: vnum("2")}},
+					}}},
+				}}}},
+			}}},
+			diags: []diag{
+				{"f:1:18", jsonast.ErrorCategorySyntax, `unexpected token "<EOF>" (expected "}")`},
+				{"f:1:18", jsonast.ErrorCategorySyntax, `unexpected token "<EOF>" (expected "]")`},
+				{"f:1:18", jsonast.ErrorCategorySyntax, `unexpected token "<EOF>" (expected "}")`},
+			},
+		},
+		{
+			name: "mismatched close and trailing garbage",
+			json: `{"a": [1}] 2`,
+			expected: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+				{Key: "a", Value: &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+					{Number: vnum("1")},
+				}}}},
+			}}},
+			diags: []diag{
+				{"f:1:9", jsonast.ErrorCategorySyntax, `unexpected token "}" (expected "]")`},
+				{"f:1:10", jsonast.ErrorCategorySyntax, `unexpected token "]"`},
+			},
+		},
+		{
+			name: "mismatched array close",
+			json: `[1}`,
+			expected: &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+				{Number: vnum("1")},
+			}}},
+			diags: []diag{
+				{"f:1:3", jsonast.ErrorCategorySyntax, `unexpected token "}" (expected "]")`},
+			},
+		},
+		{
+			name: "mismatched object close",
+			json: `{"a":1] 2`,
+			expected: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+				{Key: "a", Value: &jsonast.JsonValue{Number: vnum("1")}},
+			}}},
+			diags: []diag{
+				{"f:1:7", jsonast.ErrorCategorySyntax, `unexpected token "]" (expected "}")`},
+			},
+		},
+		{
+			name: "stray close at root",
+			json: `] ]`,
+			expected: &jsonast.JsonValue{
+				Invalid: &jsonast.JsonInvalid{},
+			},
+			diags: []diag{
+				{"f:1:1", jsonast.ErrorCategorySyntax, `unexpected token "]" (expected value)`},
+			},
+		},
+		{
+			name:     "trailing garbage reported once",
+			json:     `1 2 } x`,
+			expected: &jsonast.JsonValue{Number: vnum("1")},
+			diags: []diag{
+				{"f:1:3", jsonast.ErrorCategorySyntax, `unexpected token "2"`},
+			},
+		},
+		{
+			name: "garbage before object close",
+			json: `{"a": 1 @}`,
+			expected: &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+				{Key: "a", Value: &jsonast.JsonValue{Number: vnum("1")}},
+			}}},
+			diags: []diag{
+				{"f:1:9", jsonast.ErrorCategoryLexical, `invalid character '@'`},
+			},
+		},
+		{
+			name: "garbage before array close",
+			json: `[[1 @], 2 #]`,
+			expected: &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+				{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+					{Number: vnum("1")},
+				}}},
+				{Number: vnum("2")},
+			}}},
+			diags: []diag{
+				{"f:1:5", jsonast.ErrorCategoryLexical, `invalid character '@'`},
+				{"f:1:11", jsonast.ErrorCategoryLexical, `invalid character '#'`},
+			},
+		},
+		{
+			name: "bad escape and number",
+			json: `["\x", 01, -]`,
+			expected: &jsonast.JsonValue{Array: &jsonast.JsonArray{Elements: []*jsonast.JsonValue{
+				{Invalid: &jsonast.JsonInvalid{}},
+				{Invalid: &jsonast.JsonInvalid{}},
+				{Invalid: &jsonast.JsonInvalid{}},
+			}}},
+			diags: []diag{
+				{"f:1:2", jsonast.ErrorCategoryLexical, "invalid escape sequence `\\x` in string"},
+				{"f:1:8", jsonast.ErrorCategoryLexical, `invalid number "01"`},
+				{"f:1:12", jsonast.ErrorCategoryLexical, `invalid number "-"`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, errs := jsonast.ParseTolerant("f", []byte(tt.json))
+			diags := make([]diag, len(errs))
+
+			for i, err := range errs {
+				diags[i] = diag{err.Pos.String(), err.Category, err.Msg}
+			}
+
+			assert.Equal(t, tt.diags, diags)
+			assert.Equal(t, tt.expected, stripInvalid(stripSource(v)))
+		})
+	}
+}
+
+func TestParseTolerant_InvalidNode(t *testing.T) {
+	v, diags := jsonast.ParseTolerant("f", []byte(`[1, ?]`))
+	require.Len(t, diags, 1)
+	invalid := v.Array.Elements[1]
+	assert.True(t, invalid.IsInvalid())
+	assert.Same(t, diags[0], invalid.Invalid.Err)
+	assert.Equal(t, 5, invalid.Pos.Column)
+	assert.Equal(t, []string(nil), diags[0].Expected)
+	assert.Equal(t, "?", diags[0].Token)
+	assert.False(t, invalid.Nullable())
+	assert.Equal(t, &jsonast.JsonValue{Null: anynull()}, invalid.UnionType(&jsonast.JsonValue{Number: vnum("1")}))
+	assert.Equal(t, &jsonast.JsonValue{Null: anynull()}, (&jsonast.JsonValue{Null: vnull()}).UnionType(invalid))
+}
@@ -0,0 +1,239 @@
+package jsonast
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type scanKind int
+
+const (
+	scanEOF scanKind = iota
+	scanBeginObject
+	scanEndObject
+	scanBeginArray
+	scanEndArray
+	scanComma
+	scanColon
+	scanString
+	scanNumber
+	scanTrue
+	scanFalse
+	scanNull
+	scanInvalid
+)
+
+type scanToken struct {
+	kind    scanKind
+	leading string
+	raw     string
+	pos     lexer.Position
+	err     string
+}
+
+func (t *scanToken) String() string {
+	if t.kind == scanEOF {
+		return lexer.EOFToken(t.pos).String()
+	}
+
+	return t.raw
+}
+
+// scanner is a forgiving JSON tokenizer: malformed input becomes scanInvalid tokens rather than errors.
+type scanner struct {
+	src []byte
+	off int
+	pos lexer.Position
+}
+
+func newScanner(filename string, src []byte) *scanner {
+	return &scanner{
+		src: src,
+		pos: lexer.Position{Filename: filename, Line: 1, Column: 1},
+	}
+}
+
+func (s *scanner) next() *scanToken {
+	start := s.off
+
+	for s.off < len(s.src) && isSpace(s.src[s.off]) {
+		s.off++
+	}
+
+	leading := string(s.src[start:s.off])
+	s.pos.Advance(leading)
+	tok := &scanToken{leading: leading, pos: s.pos}
+
+	if s.off >= len(s.src) {
+		tok.kind = scanEOF
+		return tok
+	}
+
+	start = s.off
+
+	switch c := s.src[s.off]; {
+	case c == '{':
+		tok.kind = scanBeginObject
+		s.off++
+	case c == '}':
+		tok.kind = scanEndObject
+		s.off++
+	case c == '[':
+		tok.kind = scanBeginArray
+		s.off++
+	case c == ']':
+		tok.kind = scanEndArray
+		s.off++
+	case c == ',':
+		tok.kind = scanComma
+		s.off++
+	case c == ':':
+		tok.kind = scanColon
+		s.off++
+	case c == '"':
+		tok.kind, tok.err = s.scanString()
+	case c == '-' || isDigit(c):
+		tok.kind, tok.err = s.scanNumber()
+	case isWordByte(c):
+		for s.off < len(s.src) && isWordByte(s.src[s.off]) {
+			s.off++
+		}
+
+		switch word := string(s.src[start:s.off]); word {
+		case "true":
+			tok.kind = scanTrue
+		case "false":
+			tok.kind = scanFalse
+		case "null":
+			tok.kind = scanNull
+		default:
+			tok.kind = scanInvalid
+			tok.err = fmt.Sprintf("invalid literal %q", word)
+		}
+	default:
+		r, size := utf8.DecodeRune(s.src[s.off:])
+		s.off += size
+		tok.kind = scanInvalid
+
+		if r == utf8.RuneError {
+			tok.err = fmt.Sprintf("invalid character %q", s.src[start:s.off])
+		} else {
+			tok.err = fmt.Sprintf("invalid character %q", r)
+		}
+	}
+
+	tok.raw = string(s.src[start:s.off])
+	s.pos.Advance(tok.raw)
+	return tok
+}
+
+func (s *scanner) scanString() (scanKind, string) {
+	s.off++
+
+	for s.off < len(s.src) {
+		switch c := s.src[s.off]; {
+		case c == '"':
+			s.off++
+			return scanString, ""
+		case c == '\\':
+			s.off += 2
+		case c == '\n':
+			return scanInvalid, "unterminated string"
+		case c < 0x20:
+			s.off++
+			return scanInvalid, fmt.Sprintf("invalid character %q in string literal", c)
+		default:
+			s.off++
+		}
+	}
+
+	s.off = len(s.src)
+	return scanInvalid, "unterminated string"
+}
+
+func (s *scanner) scanNumber() (scanKind, string) {
+	start := s.off
+
+	for s.off < len(s.src) && isNumberByte(s.src[s.off]) {
+		s.off++
+	}
+
+	text := string(s.src[start:s.off])
+
+	if !isJSONNumber(text) {
+		return scanInvalid, fmt.Sprintf("invalid number %q", text)
+	}
+
+	return scanNumber, ""
+}
+
+func decodeString(raw string) (string, error) {
+	var s string
+	err := json.Unmarshal([]byte(raw), &s)
+	return s, err
+}
+
+// isJSONNumber reports whether all of s matches the RFC 8259 number grammar.
+func isJSONNumber(s string) bool {
+	i := 0
+	digits := func() bool {
+		start := i
+
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+
+		return i > start
+	}
+
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+
+	if i < len(s) && s[i] == '0' {
+		i++
+	} else if !digits() {
+		return false
+	}
+
+	if i < len(s) && s[i] == '.' {
+		i++
+
+		if !digits() {
+			return false
+		}
+	}
+
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+
+		if !digits() {
+			return false
+		}
+	}
+
+	return i == len(s)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(c) || c == '_'
+}
+
+func isNumberByte(c byte) bool {
+	return isDigit(c) || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+}
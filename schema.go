@@ -0,0 +1,246 @@
+package jsonast
+
+import (
+	"fmt"
+	"slices"
+)
+
+// FromJSONSchema converts a JSON Schema into the shape model produced by UnionType.
+// Keys not listed in "required" become omittable, "null" among the types makes a scalar nullable,
+// "items" becomes the array element, and schemas without constraints become any-nulls.
+// Alternatives ("anyOf", "oneOf" and multiple types) are folded with UnionType.
+func FromJSONSchema(schema *JsonValue) (*JsonValue, error) {
+	return fromSchema(schema, "")
+}
+
+func fromSchema(schema *JsonValue, path string) (*JsonValue, error) {
+	switch {
+	case schema.IsTrue():
+		return NewAny(), nil
+	case schema.IsFalse():
+		return nil, fmt.Errorf("%s: the false schema matches nothing and has no shape", path)
+	case !schema.IsObject():
+		return nil, fmt.Errorf("%s: schema must be an object or a boolean", path)
+	}
+
+	obj := schema.Object
+
+	for _, k := range []string{"$ref", "allOf", "not", "if"} {
+		if obj.Has(k) {
+			return nil, fmt.Errorf("%s: %q is not supported", path, k)
+		}
+	}
+
+	shapes := []*JsonValue{}
+
+	for _, k := range []string{"anyOf", "oneOf"} {
+		alts, ok := obj.Get(k)
+
+		if !ok {
+			continue
+		} else if !alts.IsArray() || alts.Array.Len() == 0 {
+			return nil, fmt.Errorf("%s: %q must be a non-empty array", appendPointer(path, k), k)
+		}
+
+		for i, alt := range alts.Array.Elements {
+			s, err := fromSchema(alt, appendIndexPointer(appendPointer(path, k), i))
+
+			if err != nil {
+				return nil, err
+			}
+
+			shapes = append(shapes, s)
+		}
+	}
+
+	if v, ok := obj.Get("const"); ok {
+		shapes = append(shapes, v.Clone())
+	}
+
+	if v, ok := obj.Get("enum"); ok {
+		if !v.IsArray() || v.Array.Len() == 0 {
+			return nil, fmt.Errorf("%s: \"enum\" must be a non-empty array", appendPointer(path, "enum"))
+		}
+
+		for _, e := range v.Array.Elements {
+			shapes = append(shapes, e.Clone())
+		}
+	}
+
+	types, err := schemaTypes(obj, path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range types {
+		s, err := fromSchemaType(obj, t, path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		shapes = append(shapes, s)
+	}
+
+	if len(shapes) == 0 {
+		return NewAny(), nil
+	}
+
+	shape := shapes[0]
+
+	for _, s := range shapes[1:] {
+		shape = shape.UnionType(s)
+	}
+
+	// OpenAPI 3.0 spells a nullable type as "nullable": true.
+	if v, ok := obj.Get("nullable"); ok && v.IsTrue() && !shape.IsNull() {
+		shape = shape.UnionType(NewNull())
+	}
+
+	return shape, nil
+}
+
+func schemaTypes(obj *JsonObject, path string) ([]string, error) {
+	v, ok := obj.Get("type")
+
+	switch {
+	case !ok:
+		// Infer the type from keywords that only apply to one.
+		if obj.Has("properties") || obj.Has("required") {
+			return []string{"object"}, nil
+		} else if obj.Has("items") || obj.Has("prefixItems") {
+			return []string{"array"}, nil
+		}
+
+		return nil, nil
+	case v.IsString():
+		return []string{v.String.Text}, nil
+	case v.IsArray():
+		types := []string{}
+
+		for _, e := range v.Array.Elements {
+			if !e.IsString() {
+				return nil, fmt.Errorf("%s: \"type\" must contain only strings", appendPointer(path, "type"))
+			}
+
+			types = append(types, e.String.Text)
+		}
+
+		return types, nil
+	default:
+		return nil, fmt.Errorf("%s: \"type\" must be a string or an array of strings", appendPointer(path, "type"))
+	}
+}
+
+func fromSchemaType(obj *JsonObject, t string, path string) (*JsonValue, error) {
+	switch t {
+	case "null":
+		return NewNull(), nil
+	case "boolean":
+		return NewBool(true), nil
+	case "number", "integer":
+		return NewNumberFromInt(0), nil
+	case "string":
+		return NewString(""), nil
+	case "object":
+		return fromSchemaObject(obj, path)
+	case "array":
+		return fromSchemaArray(obj, path)
+	default:
+		return nil, fmt.Errorf("%s: unknown type %q", appendPointer(path, "type"), t)
+	}
+}
+
+func fromSchemaObject(obj *JsonObject, path string) (*JsonValue, error) {
+	shape := &JsonObject{Members: []*JsonObjectMember{}, OmittableKeys: map[string]struct{}{}}
+	required := []string{}
+
+	if v, ok := obj.Get("required"); ok {
+		if !v.IsArray() {
+			return nil, fmt.Errorf("%s: \"required\" must be an array", appendPointer(path, "required"))
+		}
+
+		for _, e := range v.Array.Elements {
+			if !e.IsString() {
+				return nil, fmt.Errorf("%s: \"required\" must contain only strings", appendPointer(path, "required"))
+			}
+
+			required = append(required, e.String.Text)
+		}
+	}
+
+	if props, ok := obj.Get("properties"); ok {
+		if !props.IsObject() {
+			return nil, fmt.Errorf("%s: \"properties\" must be an object", appendPointer(path, "properties"))
+		}
+
+		for _, k := range props.Object.Keys() {
+			sub, _ := props.Object.Get(k)
+			s, err := fromSchema(sub, appendPointer(appendPointer(path, "properties"), k))
+
+			if err != nil {
+				return nil, err
+			}
+
+			shape.Members = append(shape.Members, &JsonObjectMember{Key: k, Value: s})
+
+			if !slices.Contains(required, k) {
+				shape.OmittableKeys[k] = struct{}{}
+			}
+		}
+	}
+
+	// Required keys without a property schema may hold anything.
+	for _, k := range required {
+		if !shape.Has(k) {
+			shape.Members = append(shape.Members, &JsonObjectMember{Key: k, Value: NewAny()})
+		}
+	}
+
+	return &JsonValue{Object: shape}, nil
+}
+
+func fromSchemaArray(obj *JsonObject, path string) (*JsonValue, error) {
+	elems := []*JsonValue{}
+
+	// Tuples are "prefixItems" since 2020-12 and an array-valued "items" before that.
+	for _, k := range []string{"prefixItems", "items"} {
+		v, ok := obj.Get(k)
+
+		// "items": false only forbids elements beyond "prefixItems".
+		if !ok || v.IsFalse() {
+			continue
+		}
+
+		kpath := appendPointer(path, k)
+
+		if v.IsArray() {
+			for i, e := range v.Array.Elements {
+				s, err := fromSchema(e, appendIndexPointer(kpath, i))
+
+				if err != nil {
+					return nil, err
+				}
+
+				elems = append(elems, s)
+			}
+
+			continue
+		}
+
+		s, err := fromSchema(v, kpath)
+
+		if err != nil {
+			return nil, err
+		}
+
+		elems = append(elems, s)
+	}
+
+	if len(elems) == 0 {
+		elems = append(elems, NewAny())
+	}
+
+	return (&JsonArray{Elements: elems}).UnionType(nil), nil
+}
@@ -0,0 +1,99 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestFromJSONSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected string
+	}{
+		{name: "empty", schema: `{}`, expected: `any`},
+		{name: "true", schema: `true`, expected: `any`},
+		{name: "scalars", schema: `{"type":"array","items":{"type":["integer","null"]}}`, expected: `[number|null]`},
+		{name: "multiple types", schema: `{"type":["string","boolean"]}`, expected: `any`},
+		{name: "null", schema: `{"type":"null"}`, expected: `null`},
+		{name: "items missing", schema: `{"type":"array"}`, expected: `[any]`},
+		{
+			name: "object",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer"},
+					"name": {"type": ["string", "null"]},
+					"tags": {"type": "array", "items": {"type": "string"}},
+					"meta": {}
+				},
+				"required": ["id", "tags", "meta", "extra"]
+			}`,
+			expected: `{id: number, name?: string|null, tags: [string], meta: any, extra: any}`,
+		},
+		{name: "implicit object", schema: `{"properties":{"a":{"type":"boolean"}}}`, expected: `{a?: boolean}`},
+		{name: "tuple", schema: `{"prefixItems":[{"type":"number"},{"type":"null"}],"items":false}`, expected: `[number|null]`},
+		{name: "draft 4 tuple", schema: `{"type":"array","items":[{"type":"string"},{"type":"string"}]}`, expected: `[string]`},
+		{name: "anyOf", schema: `{"anyOf":[{"type":"string"},{"type":"null"}]}`, expected: `string|null`},
+		{
+			name:     "oneOf objects",
+			schema:   `{"oneOf":[{"properties":{"a":{"type":"string"}},"required":["a"]},{"properties":{"b":{"type":"number"}},"required":["b"]}]}`,
+			expected: `{a?: string, b?: number}`,
+		},
+		{name: "enum", schema: `{"enum":["a","b",null]}`, expected: `string|null`},
+		{name: "const", schema: `{"const":1}`, expected: `number`},
+		{name: "openapi nullable", schema: `{"type":"string","nullable":true}`, expected: `string|null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := jsonast.ParseBytes("schema.json", []byte(tt.schema))
+			require.NoError(t, err)
+			shape, err := jsonast.FromJSONSchema(schema)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, shape.TypeString())
+		})
+	}
+}
+
+func TestFromJSONSchema_Error(t *testing.T) {
+	tests := []struct {
+		schema   string
+		expected string
+	}{
+		{`false`, `: the false schema matches nothing and has no shape`},
+		{`1`, `: schema must be an object or a boolean`},
+		{`{"$ref":"#/defs/a"}`, `: "$ref" is not supported`},
+		{`{"type":"date"}`, `/type: unknown type "date"`},
+		{`{"type":1}`, `/type: "type" must be a string or an array of strings`},
+		{`{"properties":{"a/b":{"items":{"allOf":[]}}}}`, `/properties/a~1b/items: "allOf" is not supported`},
+		{`{"anyOf":[]}`, `/anyOf: "anyOf" must be a non-empty array`},
+		{`{"required":"a"}`, `/required: "required" must be an array`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			schema, err := jsonast.ParseBytes("schema.json", []byte(tt.schema))
+			require.NoError(t, err)
+			_, err = jsonast.FromJSONSchema(schema)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestFromJSONSchema_Union(t *testing.T) {
+	schema, err := jsonast.ParseBytes("schema.json", []byte(`{"properties":{"id":{"type":"integer"},"name":{"type":"string"}},"required":["id","name"]}`))
+	require.NoError(t, err)
+	shape, err := jsonast.FromJSONSchema(schema)
+	require.NoError(t, err)
+
+	inferred := inferShape(t, `{"id":1,"name":null,"note":"n"}`)
+	assert.Equal(t, `{id: number, name: string|null, note?: string}`, shape.UnionType(inferred).TypeString())
+
+	doc, err := jsonast.ParseBytes("doc.json", []byte(`{"id":1}`))
+	require.NoError(t, err)
+	assert.Len(t, jsonast.Conforms(doc, shape, nil), 1)
+}
@@ -0,0 +1,86 @@
+package jsonast
+
+import (
+	"strings"
+)
+
+// TypeString renders the shape of v as a compact signature,
+// e.g. {id: number, name?: string|null, tags: [string], meta: any}.
+// Arrays are folded to the union of their elements.
+func (v *JsonValue) TypeString() string {
+	b := &strings.Builder{}
+	v.appendTypeString(b)
+	return b.String()
+}
+
+func (v *JsonValue) appendTypeString(b *strings.Builder) {
+	switch o := v.Value().(type) {
+	case *JsonFalse, *JsonTrue:
+		b.WriteString("boolean")
+	case *JsonNull:
+		if o.any {
+			b.WriteString("any")
+		} else {
+			b.WriteString("null")
+		}
+	case *JsonNumber:
+		b.WriteString("number")
+	case *JsonString:
+		b.WriteString("string")
+	case *JsonObject:
+		b.WriteString("{")
+
+		for i, k := range o.Keys() {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+
+			if isIdentifier(k) {
+				b.WriteString(k)
+			} else {
+				b.WriteString(quote(k))
+			}
+
+			if o.isOmittable(k) {
+				b.WriteString("?")
+			}
+
+			b.WriteString(": ")
+			e, _ := o.Get(k)
+			e.appendTypeString(b)
+		}
+
+		b.WriteString("}")
+	case *JsonArray:
+		b.WriteString("[")
+
+		if len(o.Elements) > 0 {
+			o.UnionType(nil).Array.Elements[0].appendTypeString(b)
+		}
+
+		b.WriteString("]")
+	default:
+		b.WriteString("invalid")
+	}
+
+	if t := v.Value(); t != nil && t.Nullable() {
+		b.WriteString("|null")
+	}
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, c := range s {
+		switch {
+		case c == '_' || c == '$' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+		case i > 0 && '0' <= c && c <= '9':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
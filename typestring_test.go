@@ -0,0 +1,70 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestTypeString(t *testing.T) {
+	tests := []struct {
+		name     string
+		docs     []string
+		expected string
+	}{
+		{
+			name:     "scalars",
+			docs:     []string{`[1,"s",true,false,null]`},
+			expected: `[any]`,
+		},
+		{
+			name:     "null",
+			docs:     []string{`null`},
+			expected: `null`,
+		},
+		{
+			name:     "empty",
+			docs:     []string{`{"a":[],"b":{}}`},
+			expected: `{a: [], b: {}}`,
+		},
+		{
+			name: "union",
+			docs: []string{
+				`{"id":1,"name":"a","tags":["x"],"meta":1,"ok":true}`,
+				`{"id":2,"name":null,"tags":[],"meta":"m","ok":false,"my key":{"x-y":1}}`,
+				`{"id":3,"tags":["y","z"],"ok":null}`,
+			},
+			expected: `{id: number, name?: string|null, tags: [string], meta?: any, ok: boolean|null, "my key"?: {"x-y": number}}`,
+		},
+		{
+			name:     "array folding",
+			docs:     []string{`[[1,null],[2]]`},
+			expected: `[[number|null]]`,
+		},
+		{
+			name:     "duplicate keys",
+			docs:     []string{`{"a":1,"a":"s"}`},
+			expected: `{a: string}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inf := jsonast.NewInferrer(0)
+
+			for _, doc := range tt.docs {
+				require.NoError(t, inf.AddBytes("", []byte(doc)))
+			}
+
+			assert.Equal(t, tt.expected, inf.Shape().TypeString())
+		})
+	}
+}
+
+func TestTypeString_Invalid(t *testing.T) {
+	v, _ := jsonast.ParseTolerant("", []byte(`[x]`))
+	assert.Equal(t, "[invalid]", stripInvalid(v).TypeString())
+	assert.Equal(t, "invalid", (&jsonast.JsonValue{}).TypeString())
+}
@@ -2,8 +2,25 @@ package jsonast
 
 import "fmt"
 
+// UnionType returns the shape that covers both v and other.
+// The result shares no nodes, slices or maps with either input.
 func (v *JsonValue) UnionType(other *JsonValue) *JsonValue {
-	return v.Value().UnionType(other)
+	return v.union(other, false)
+}
+
+// union is UnionType for trees the library owns: when owned is set, nodes of v and other
+// are reused in the result instead of cloned, so neither input may be used afterwards.
+func (v *JsonValue) union(other *JsonValue, owned bool) *JsonValue {
+	switch o := v.Value().(type) {
+	case *JsonNull:
+		return o.union(other, owned)
+	case *JsonArray:
+		return o.union(other, owned)
+	case *JsonObject:
+		return o.union(other, owned)
+	default:
+		return o.UnionType(other)
+	}
 }
 
 func (v *JsonTrue) UnionType(other *JsonValue) *JsonValue {
@@ -27,6 +44,10 @@ func (v *JsonFalse) UnionType(other *JsonValue) *JsonValue {
 }
 
 func (v *JsonNull) UnionType(other *JsonValue) *JsonValue {
+	return v.union(other, false)
+}
+
+func (v *JsonNull) union(other *JsonValue, owned bool) *JsonValue {
 	switch o := other.Value().(type) {
 	case *JsonFalse:
 		newval := &JsonFalse{}
@@ -37,11 +58,17 @@ func (v *JsonNull) UnionType(other *JsonValue) *JsonValue {
 		newval.nullable = true
 		return &JsonValue{True: newval}
 	case *JsonObject:
-		newval := &JsonObject{Members: o.Members}
-		return &JsonValue{Object: newval}
+		if owned {
+			return &JsonValue{Object: o}
+		}
+
+		return &JsonValue{Object: o.clone()}
 	case *JsonArray:
-		newval := &JsonArray{Elements: o.Elements}
-		return &JsonValue{Array: newval}
+		if owned {
+			return &JsonValue{Array: o}
+		}
+
+		return &JsonValue{Array: o.clone()}
 	case *JsonNumber:
 		newval := &JsonNumber{Text: o.Text}
 		newval.nullable = true
@@ -53,6 +80,8 @@ func (v *JsonNull) UnionType(other *JsonValue) *JsonValue {
 	case *JsonNull:
 		newval := &JsonNull{any: v.any || o.any}
 		return &JsonValue{Null: newval}
+	case *JsonInvalid:
+		return &JsonValue{Null: &JsonNull{any: true}}
 	default:
 		panic(fmt.Sprintf("unexpected type: %+v", o))
 	}
@@ -78,11 +107,20 @@ func (v *JsonString) UnionType(other *JsonValue) *JsonValue {
 	}
 }
 
+func (v *JsonInvalid) UnionType(other *JsonValue) *JsonValue {
+	return &JsonValue{Null: &JsonNull{any: true}}
+}
+
 func (v *JsonArray) UnionType(other *JsonValue) *JsonValue {
+	return v.union(other, false)
+}
+
+func (v *JsonArray) union(other *JsonValue, owned bool) *JsonValue {
 	if other != nil {
-		if other.IsNull() {
-			newval := &JsonArray{Elements: v.Elements}
-			return &JsonValue{Array: newval}
+		if other.IsNull() && owned {
+			return &JsonValue{Array: v}
+		} else if other.IsNull() {
+			return &JsonValue{Array: v.clone()}
 		} else if !other.IsArray() {
 			return &JsonValue{Null: &JsonNull{any: true}}
 		}
@@ -99,15 +137,19 @@ func (v *JsonArray) UnionType(other *JsonValue) *JsonValue {
 	elems := make([]*JsonValue, 0, len(v.Elements)+len(other.Array.Elements))
 	elems = append(elems, v.Elements...)
 	elems = append(elems, other.Array.Elements...)
-	union := elems[0]
-	elems = elems[1:]
+	first := elems[0]
+	union := first
 
-	for _, e := range elems {
+	for _, e := range elems[1:] {
 		if union.IsNull() && union.Null.any {
 			break
 		}
 
-		union = union.UnionType(e)
+		union = union.union(e, owned)
+	}
+
+	if union == first && !owned {
+		union = first.Clone()
 	}
 
 	return &JsonValue{
@@ -118,66 +160,76 @@ func (v *JsonArray) UnionType(other *JsonValue) *JsonValue {
 }
 
 func (v *JsonObject) UnionType(other *JsonValue) *JsonValue {
-	if other.IsNull() {
-		newval := &JsonObject{
-			Members:       v.Members,
-			OmittableKeys: v.OmittableKeys,
-		}
-		return &JsonValue{Object: newval}
+	return v.union(other, false)
+}
+
+func (v *JsonObject) union(other *JsonValue, owned bool) *JsonValue {
+	if other.IsNull() && owned {
+		return &JsonValue{Object: v}
+	} else if other.IsNull() {
+		return &JsonValue{Object: v.clone()}
 	} else if !other.IsObject() {
 		return &JsonValue{Null: &JsonNull{any: true}}
 	}
 
 	type entry struct {
 		member *JsonObjectMember
-		order  int
 		keycnt int
 	}
 
-	members := make(map[string]*entry, len(v.Members)+len(other.Object.Members))
+	entries := make([]entry, 0, len(v.Members)+len(other.Object.Members))
+	index := make(map[string]int, cap(entries))
 
-	for i, m := range v.Members {
-		members[m.Key] = &entry{member: m, order: i, keycnt: 1}
+	for _, m := range v.Members {
+		if i, ok := index[m.Key]; ok {
+			entries[i].member = m
+		} else {
+			index[m.Key] = len(entries)
+			entries = append(entries, entry{member: m, keycnt: 1})
+		}
 	}
 
-	midx := len(members)
-
 	for _, omem := range other.Object.Members {
 		k := omem.Key
-		var ent *entry
 
-		if e, ok := members[k]; ok {
-			union := e.member.Value.UnionType(omem.Value)
+		if i, ok := index[k]; ok {
+			e := &entries[i]
+			union := e.member.Value.union(omem.Value, owned)
 			e.member = &JsonObjectMember{Key: k, Value: union}
 			e.keycnt += 1
-			ent = e
 		} else {
-			ent = &entry{
-				member: omem,
-				order:  midx,
-				keycnt: 1,
-			}
-			midx++
+			index[k] = len(entries)
+			entries = append(entries, entry{member: omem, keycnt: 1})
 		}
-
-		members[k] = ent
 	}
 
-	entries := make([]*JsonObjectMember, len(members))
+	members := make([]*JsonObjectMember, len(entries))
 	omittableKeys := map[string]struct{}{}
 
-	for k, e := range members {
-		entries[e.order] = e.member
+	for i, e := range entries {
+		k := e.member.Key
+
+		// Members seen once still belong to an input tree.
+		if e.keycnt == 1 && !owned {
+			members[i] = e.member.clone()
+		} else {
+			members[i] = e.member
+		}
 
-		if e.keycnt == 1 {
+		if e.keycnt == 1 || v.isOmittable(k) || other.Object.isOmittable(k) {
 			omittableKeys[k] = struct{}{}
 		}
 	}
 
 	return &JsonValue{
 		Object: &JsonObject{
-			Members:       entries,
+			Members:       members,
 			OmittableKeys: omittableKeys,
 		},
 	}
 }
+
+func (v *JsonObject) isOmittable(key string) bool {
+	_, ok := v.OmittableKeys[key]
+	return ok
+}
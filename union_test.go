@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/winebarrel/jsonast"
 )
 
@@ -1274,6 +1275,30 @@ func TestObjectUnionType(t *testing.T) {
 				OmittableKeys: map[string]struct{}{},
 			}},
 		},
+		{
+			name: "omittable object <=> object",
+			value: &jsonast.JsonObject{
+				Members: []*jsonast.JsonObjectMember{
+					{Key: "str", Value: &jsonast.JsonValue{String: vstr("s")}},
+					{Key: "num", Value: &jsonast.JsonValue{Number: vnum("1")}},
+				},
+				OmittableKeys: map[string]struct{}{"str": {}},
+			},
+			other: &jsonast.JsonValue{Object: &jsonast.JsonObject{
+				Members: []*jsonast.JsonObjectMember{
+					{Key: "str", Value: &jsonast.JsonValue{String: vstr("s")}},
+					{Key: "num", Value: &jsonast.JsonValue{Number: vnum("1")}},
+				},
+				OmittableKeys: map[string]struct{}{"num": {}},
+			}},
+			expected: &jsonast.JsonValue{Object: &jsonast.JsonObject{
+				Members: []*jsonast.JsonObjectMember{
+					{Key: "str", Value: &jsonast.JsonValue{String: vstr("s")}},
+					{Key: "num", Value: &jsonast.JsonValue{Number: vnum("1")}},
+				},
+				OmittableKeys: map[string]struct{}{"str": {}, "num": {}},
+			}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1283,3 +1308,59 @@ func TestObjectUnionType(t *testing.T) {
 		})
 	}
 }
+
+func mutate(v *jsonast.JsonValue) {
+	switch {
+	case v.IsString():
+		v.String.Text = "mutated"
+	case v.IsNumber():
+		v.Number.Text = "-1"
+	case v.IsArray():
+		for _, e := range v.Array.Elements {
+			mutate(e)
+		}
+
+		v.Array.Elements = append(v.Array.Elements, &jsonast.JsonValue{True: vtrue()})
+	case v.IsObject():
+		for _, m := range v.Object.Members {
+			m.Key += "!"
+			mutate(m.Value)
+		}
+
+		v.Object.Members = append(v.Object.Members, &jsonast.JsonObjectMember{Key: "new", Value: &jsonast.JsonValue{Null: vnull()}})
+
+		if v.Object.OmittableKeys != nil {
+			v.Object.OmittableKeys["new"] = struct{}{}
+		}
+	}
+}
+
+func TestUnionType_NoSharing(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		other string
+	}{
+		{name: "null <=> object", value: `null`, other: `{"a":[1]}`},
+		{name: "null <=> array", value: `null`, other: `[{"a":"s"}]`},
+		{name: "object <=> null", value: `{"a":[1]}`, other: `null`},
+		{name: "array <=> null", value: `[{"a":"s"}]`, other: `null`},
+		{name: "array <=> array", value: `[{"a":1}]`, other: `[]`},
+		{name: "array <=> array any", value: `[{"a":1},"s"]`, other: `[1]`},
+		{name: "object <=> object", value: `{"a":{"b":"x"},"c":1,"c":{"d":[2]}}`, other: `{"e":2,"f":[{"g":true}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := jsonast.ParseBytes("value.json", []byte(tt.value))
+			require.NoError(t, err)
+			other, err := jsonast.ParseBytes("other.json", []byte(tt.other))
+			require.NoError(t, err)
+			valueCopy, otherCopy := value.Clone(), other.Clone()
+
+			mutate(value.UnionType(other))
+			assert.Equal(t, valueCopy, value)
+			assert.Equal(t, otherCopy, other)
+		})
+	}
+}
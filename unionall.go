@@ -0,0 +1,75 @@
+package jsonast
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+func UnionAll(ctx context.Context, values []*JsonValue, workers int) (*JsonValue, error) {
+	return UnionAllWithProgress(ctx, values, workers, nil)
+}
+
+// UnionAllWithProgress reduces values pairwise, level by level, across workers goroutines.
+// As with UnionType, the result shares no nodes with values, except that a single value is returned as is.
+// progress, if non-nil, is called concurrently after each UnionType with the number of unions done out of len(values)-1.
+func UnionAllWithProgress(ctx context.Context, values []*JsonValue, workers int, progress func(done, total int)) (*JsonValue, error) {
+	if len(values) == 0 {
+		return nil, ctx.Err()
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	total := len(values) - 1
+	var done atomic.Int64
+	level := values
+	// Only the first level reads the caller's values; later levels union trees built here.
+	owned := false
+
+	for len(level) > 1 {
+		next := make([]*JsonValue, (len(level)+1)/2)
+		pairs := make(chan int)
+		var wg sync.WaitGroup
+
+		for range min(workers, len(level)/2) {
+			wg.Go(func() {
+				for i := range pairs {
+					next[i] = level[2*i].union(level[2*i+1], owned)
+
+					if progress != nil {
+						progress(int(done.Add(1)), total)
+					}
+				}
+			})
+		}
+
+	dispatch:
+		for i := range len(level) / 2 {
+			select {
+			case pairs <- i:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+
+		close(pairs)
+		wg.Wait()
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if last := level[len(level)-1]; len(level)%2 == 1 && !owned {
+			next[len(next)-1] = last.Clone()
+		} else if len(level)%2 == 1 {
+			next[len(next)-1] = last
+		}
+
+		level = next
+		owned = true
+	}
+
+	return level[0], nil
+}
@@ -0,0 +1,124 @@
+package jsonast_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestUnionAll(t *testing.T) {
+	values := make([]*jsonast.JsonValue, 0, 101)
+
+	for i := range 101 {
+		src := fmt.Sprintf(`{"id":%d,"k%d":"v"}`, i, i%3)
+
+		if i%10 == 0 {
+			src = fmt.Sprintf(`{"id":null,"k%d":"v"}`, i%3)
+		}
+
+		v, err := jsonast.ParseBytes("<filename>", []byte(src))
+		require.NoError(t, err)
+		values = append(values, v)
+	}
+
+	expected := values[0]
+
+	for _, v := range values[1:] {
+		expected = expected.UnionType(v)
+	}
+
+	for _, workers := range []int{0, 1, 4, 200} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			union, err := jsonast.UnionAll(context.Background(), values, workers)
+			require.NoError(t, err)
+			assert.Equal(t, expected, union)
+		})
+	}
+}
+
+func TestUnionAll_NoSharing(t *testing.T) {
+	docs := []string{`null`, `{"a":[{"b":1}]}`, `{"c":{"d":"s"}}`, `null`, `[{"e":true}]`}
+
+	for n := 2; n <= len(docs); n++ {
+		t.Run(fmt.Sprintf("values=%d", n), func(t *testing.T) {
+			values := make([]*jsonast.JsonValue, n)
+			copies := make([]*jsonast.JsonValue, n)
+
+			for i := range n {
+				v, err := jsonast.ParseBytes("<filename>", []byte(docs[i]))
+				require.NoError(t, err)
+				values[i], copies[i] = v, v.Clone()
+			}
+
+			union, err := jsonast.UnionAll(context.Background(), values, 2)
+			require.NoError(t, err)
+			mutate(union)
+			assert.Equal(t, copies, values)
+		})
+	}
+}
+
+func TestUnionAll_Empty(t *testing.T) {
+	union, err := jsonast.UnionAll(context.Background(), nil, 4)
+	require.NoError(t, err)
+	assert.Nil(t, union)
+
+	v := &jsonast.JsonValue{String: vstr("s")}
+	union, err = jsonast.UnionAll(context.Background(), []*jsonast.JsonValue{v}, 4)
+	require.NoError(t, err)
+	assert.Same(t, v, union)
+}
+
+func TestUnionAllWithProgress(t *testing.T) {
+	values := make([]*jsonast.JsonValue, 10)
+
+	for i := range values {
+		values[i] = &jsonast.JsonValue{Number: vnum(fmt.Sprint(i))}
+	}
+
+	var mu sync.Mutex
+	var calls []int
+
+	union, err := jsonast.UnionAllWithProgress(context.Background(), values, 3, func(done, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 9, total)
+		calls = append(calls, done)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, &jsonast.JsonValue{Number: vnum("0")}, union)
+	assert.ElementsMatch(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, calls)
+}
+
+func TestUnionAll_Canceled(t *testing.T) {
+	values := make([]*jsonast.JsonValue, 100)
+
+	for i := range values {
+		values[i] = &jsonast.JsonValue{String: vstr("s")}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	union, err := jsonast.UnionAll(ctx, values, 4)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, union)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	union, err = jsonast.UnionAllWithProgress(ctx, values, 1, func(done, total int) {
+		if done == 10 {
+			cancel()
+		}
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, union)
+}
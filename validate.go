@@ -0,0 +1,223 @@
+package jsonast
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type ViolationKind int
+
+const (
+	ViolationInvalidUTF8 ViolationKind = iota
+	ViolationLoneSurrogate
+	ViolationNumberRange
+	ViolationNumberPrecision
+	ViolationDuplicateKey
+	ViolationNonContainerRoot
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case ViolationInvalidUTF8:
+		return "invalid UTF-8"
+	case ViolationLoneSurrogate:
+		return "lone surrogate"
+	case ViolationNumberRange:
+		return "number out of range"
+	case ViolationNumberPrecision:
+		return "number exceeds precision"
+	case ViolationDuplicateKey:
+		return "duplicate key"
+	case ViolationNonContainerRoot:
+		return "non-container root"
+	default:
+		return fmt.Sprintf("ViolationKind(%d)", int(k))
+	}
+}
+
+type Violation struct {
+	Kind ViolationKind
+	Path string
+	Pos  lexer.Position
+	Msg  string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s: %s: %s", v.Pos, v.Path, v.Msg)
+}
+
+type ValidateOptions struct {
+	RequireContainerRoot bool
+}
+
+// maxSafeInteger is 2^53-1, the largest integer that every IEEE 754 double consumer reads exactly.
+var maxSafeInteger = big.NewInt(1<<53 - 1)
+
+// ValidateIJSON reports everything in v that is not interoperable per RFC 7493 (I-JSON).
+func ValidateIJSON(v *JsonValue, opts *ValidateOptions) []*Violation {
+	if opts == nil {
+		opts = &ValidateOptions{}
+	}
+
+	violations := []*Violation{}
+
+	if opts.RequireContainerRoot && !v.IsObject() && !v.IsArray() {
+		violations = append(violations, &Violation{
+			Kind: ViolationNonContainerRoot,
+			Pos:  v.Pos,
+			Msg:  "top-level value must be an object or an array",
+		})
+	}
+
+	validateIJSON(v, "", &violations)
+	return violations
+}
+
+func validateIJSON(v *JsonValue, path string, violations *[]*Violation) {
+	report := func(kind ViolationKind, path string, pos lexer.Position, msg string) {
+		*violations = append(*violations, &Violation{Kind: kind, Path: path, Pos: pos, Msg: msg})
+	}
+
+	switch o := v.Value().(type) {
+	case *JsonString:
+		if kind, msg, ok := validateIJSONString(o.Raw, o.Text); !ok {
+			report(kind, path, v.Pos, msg)
+		}
+	case *JsonNumber:
+		if kind, msg, ok := validateIJSONNumber(o.Text); !ok {
+			report(kind, path, v.Pos, msg)
+		}
+	case *JsonObject:
+		seen := make(map[string]lexer.Position, len(o.Members))
+
+		for _, m := range o.Members {
+			mpath := appendPointer(path, m.Key)
+
+			if kind, msg, ok := validateIJSONString(m.RawKey, m.Key); !ok {
+				report(kind, mpath, m.Pos, "key: "+msg)
+			}
+
+			if first, ok := seen[m.Key]; ok {
+				report(ViolationDuplicateKey, mpath, m.Pos, fmt.Sprintf("duplicate key %q (first defined at %s)", m.Key, first))
+			} else {
+				seen[m.Key] = m.Pos
+			}
+
+			validateIJSON(m.Value, mpath, violations)
+		}
+	case *JsonArray:
+		for i, e := range o.Elements {
+			validateIJSON(e, appendIndexPointer(path, i), violations)
+		}
+	}
+}
+
+func validateIJSONString(raw string, text string) (ViolationKind, string, bool) {
+	if raw == "" {
+		if !utf8.ValidString(text) {
+			return ViolationInvalidUTF8, "string is not valid UTF-8", false
+		}
+
+		return 0, "", true
+	}
+
+	s := strings.TrimSuffix(strings.TrimPrefix(raw, `"`), `"`)
+
+	for i := 0; i < len(s); {
+		if s[i] != '\\' {
+			r, size := utf8.DecodeRuneInString(s[i:])
+
+			if r == utf8.RuneError && size == 1 {
+				return ViolationInvalidUTF8, fmt.Sprintf("string is not valid UTF-8 at byte %d", i), false
+			}
+
+			i += size
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] != 'u' {
+			i += 2
+			continue
+		}
+
+		r1, ok := parseUnicodeEscape(s[i:])
+
+		if !ok {
+			i += 2
+			continue
+		}
+
+		i += 6
+
+		if !utf16.IsSurrogate(r1) {
+			continue
+		}
+
+		if r1 < 0xdc00 {
+			if r2, ok := parseUnicodeEscape(s[i:]); ok && 0xdc00 <= r2 && r2 <= 0xdfff {
+				i += 6
+				continue
+			}
+		}
+
+		return ViolationLoneSurrogate, fmt.Sprintf("lone surrogate %s", s[i-6:i]), false
+	}
+
+	return 0, "", true
+}
+
+func parseUnicodeEscape(s string) (rune, bool) {
+	if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(s[2:6], 16, 32)
+	return rune(n), err == nil
+}
+
+func validateIJSONNumber(text string) (ViolationKind, string, bool) {
+	f, err := strconv.ParseFloat(text, 64)
+
+	if errors.Is(err, strconv.ErrRange) {
+		return ViolationNumberRange, fmt.Sprintf("number %s is out of IEEE 754 double range", text), false
+	} else if err != nil {
+		return 0, "", true
+	} else if f == 0 && strings.ContainsAny(mantissa(text), "123456789") {
+		return ViolationNumberRange, fmt.Sprintf("number %s underflows IEEE 754 double", text), false
+	}
+
+	// Integer-valued numbers are checked against the safe range whatever their notation.
+	num := &JsonNumber{Text: text}
+
+	if _, digits, exp, err := num.decompose(); err == nil && exp >= 0 {
+		// The safe range has at most 16 digits, so longer integers need not be built.
+		if int64(len(digits))+exp > 16 {
+			return ViolationNumberPrecision, fmt.Sprintf("integer %s is outside [-(2**53)+1, (2**53)-1]", text), false
+		} else if n, err := num.BigInt(); err == nil && n.Abs(n).Cmp(maxSafeInteger) > 0 {
+			return ViolationNumberPrecision, fmt.Sprintf("integer %s is outside [-(2**53)+1, (2**53)-1]", text), false
+		}
+
+		return 0, "", true
+	}
+
+	if normalizeNumber(strconv.FormatFloat(f, 'e', -1, 64)) != normalizeNumber(text) {
+		return ViolationNumberPrecision, fmt.Sprintf("number %s cannot be represented exactly as IEEE 754 double", text), false
+	}
+
+	return 0, "", true
+}
+
+func mantissa(text string) string {
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		return text[:i]
+	}
+
+	return text
+}
@@ -0,0 +1,92 @@
+package jsonast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/winebarrel/jsonast"
+)
+
+func TestValidateIJSON(t *testing.T) {
+	json := "{\n" +
+		`"ok": ["plain", "😀", "é", 9007199254740991, -9007199254740991, 1.5e-308, 0.0, 1e-300, 9007199254740991.0, 1.0e3, 0.1],` + "\n" +
+		`"lone": ["\ud800x", "\udc00", "\ud83d\ud83d"],` + "\n" +
+		"\"utf8\": \"a\xffb\", \"k\xfe\": 1,\n" +
+		`"nums": [9007199254740992, -12345678901234567890, 1e309, -1e400, 1e-400, 0e-400, 9007199254740993.0, 12345678901234567890e0, 3.141592653589793238462643383279, 1.5e308],` + "\n" +
+		`"dup": 1, "obj": {"a/b": 1, "a/b": 2}, "dup": 2` + "\n" +
+		"}"
+
+	v, err := jsonast.ParseBytes("<filename>", []byte(json))
+	require.NoError(t, err)
+
+	type violation struct {
+		Kind jsonast.ViolationKind
+		Path string
+		Pos  string
+	}
+
+	var actual []violation
+
+	for _, vio := range jsonast.ValidateIJSON(v, nil) {
+		actual = append(actual, violation{vio.Kind, vio.Path, vio.Pos.String()})
+	}
+
+	assert.Equal(t, []violation{
+		{jsonast.ViolationLoneSurrogate, "/lone/0", "<filename>:3:10"},
+		{jsonast.ViolationLoneSurrogate, "/lone/1", "<filename>:3:21"},
+		{jsonast.ViolationLoneSurrogate, "/lone/2", "<filename>:3:31"},
+		{jsonast.ViolationInvalidUTF8, "/utf8", "<filename>:4:9"},
+		{jsonast.ViolationInvalidUTF8, "/k\ufffd", "<filename>:4:16"},
+		{jsonast.ViolationNumberPrecision, "/nums/0", "<filename>:5:10"},
+		{jsonast.ViolationNumberPrecision, "/nums/1", "<filename>:5:28"},
+		{jsonast.ViolationNumberRange, "/nums/2", "<filename>:5:51"},
+		{jsonast.ViolationNumberRange, "/nums/3", "<filename>:5:58"},
+		{jsonast.ViolationNumberRange, "/nums/4", "<filename>:5:66"},
+		{jsonast.ViolationNumberPrecision, "/nums/6", "<filename>:5:82"},
+		{jsonast.ViolationNumberPrecision, "/nums/7", "<filename>:5:102"},
+		{jsonast.ViolationNumberPrecision, "/nums/8", "<filename>:5:126"},
+		{jsonast.ViolationNumberPrecision, "/nums/9", "<filename>:5:160"},
+		{jsonast.ViolationDuplicateKey, "/obj/a~1b", "<filename>:6:29"},
+		{jsonast.ViolationDuplicateKey, "/dup", "<filename>:6:40"},
+	}, actual)
+}
+
+func TestValidateIJSON_Messages(t *testing.T) {
+	v, err := jsonast.ParseBytes("<filename>", []byte(`{"a":"\ud800","b":12345678901234567890,"c":1e999,"a":1,"d":0.12345678901234567890}`))
+	require.NoError(t, err)
+	violations := jsonast.ValidateIJSON(v, &jsonast.ValidateOptions{})
+	require.Len(t, violations, 5)
+	assert.EqualError(t, violations[0], `<filename>:1:6: /a: lone surrogate \ud800`)
+	assert.EqualError(t, violations[1], `<filename>:1:19: /b: integer 12345678901234567890 is outside [-(2**53)+1, (2**53)-1]`)
+	assert.EqualError(t, violations[2], `<filename>:1:44: /c: number 1e999 is out of IEEE 754 double range`)
+	assert.EqualError(t, violations[3], `<filename>:1:50: /a: duplicate key "a" (first defined at <filename>:1:2)`)
+	assert.EqualError(t, violations[4], `<filename>:1:60: /d: number 0.12345678901234567890 cannot be represented exactly as IEEE 754 double`)
+}
+
+func TestValidateIJSON_Root(t *testing.T) {
+	v, err := jsonast.ParseBytes("<filename>", []byte(`"str"`))
+	require.NoError(t, err)
+	assert.Empty(t, jsonast.ValidateIJSON(v, nil))
+
+	violations := jsonast.ValidateIJSON(v, &jsonast.ValidateOptions{RequireContainerRoot: true})
+	require.Len(t, violations, 1)
+	assert.Equal(t, jsonast.ViolationNonContainerRoot, violations[0].Kind)
+	assert.Equal(t, "", violations[0].Path)
+
+	v, err = jsonast.ParseBytes("<filename>", []byte(`[]`))
+	require.NoError(t, err)
+	assert.Empty(t, jsonast.ValidateIJSON(v, &jsonast.ValidateOptions{RequireContainerRoot: true}))
+}
+
+func TestValidateIJSON_Built(t *testing.T) {
+	v := &jsonast.JsonValue{Object: &jsonast.JsonObject{Members: []*jsonast.JsonObjectMember{
+		{Key: "s", Value: &jsonast.JsonValue{String: vstr("ok\xc0")}},
+		{Key: "t", Value: &jsonast.JsonValue{String: vstr("ok")}},
+	}}}
+
+	violations := jsonast.ValidateIJSON(v, nil)
+	require.Len(t, violations, 1)
+	assert.Equal(t, jsonast.ViolationInvalidUTF8, violations[0].Kind)
+	assert.Equal(t, "/s", violations[0].Path)
+}